@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+
+	"auto-spotify/internal/cache"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCacheCmd creates the cache command with its stats and clear subcommands
+func NewCacheCmd(searchCache *cache.Cache) *cobra.Command {
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect or clear the local search result cache",
+		Long: `Auto-Spotify caches resolved Spotify tracks on disk so rebuilding the same playlist
+doesn't re-query Spotify for every song. Entries expire after SPOTIFY_CACHE_TTL
+(default 720h); set it to 0 to disable the cache.
+
+Examples:
+  auto-spotify cache stats            # Show cache location and entry counts
+  auto-spotify cache clear            # Remove every cached entry
+  auto-spotify cache clear --expired  # Remove only expired entries`,
+	}
+
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show search cache statistics",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if searchCache == nil {
+				return fmt.Errorf("search cache is disabled")
+			}
+
+			stats := searchCache.Stats()
+			fmt.Printf("📦 Search Cache:\n")
+			fmt.Printf("  📁 Path: %s\n", stats.Path)
+			fmt.Printf("  ⏱️  TTL: %s\n", searchCache.TTL())
+			fmt.Printf("  📋 Entries: %d\n", stats.Entries)
+			fmt.Printf("  ✅ Fresh: %d\n", stats.Fresh)
+			fmt.Printf("  ⌛ Expired: %d\n", stats.Expired)
+			fmt.Printf("  💾 Size: %d bytes\n", stats.Size)
+			return nil
+		},
+	}
+
+	var expiredOnly bool
+	clearCmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Remove entries from the search cache",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if searchCache == nil {
+				return fmt.Errorf("search cache is disabled")
+			}
+
+			var removed int
+			if expiredOnly {
+				removed = searchCache.Prune()
+			} else {
+				removed = searchCache.Clear()
+			}
+
+			if err := searchCache.Save(); err != nil {
+				return fmt.Errorf("failed to save search cache: %w", err)
+			}
+
+			fmt.Printf("🧹 Removed %d cached entries\n", removed)
+			return nil
+		},
+	}
+	clearCmd.Flags().BoolVar(&expiredOnly, "expired", false, "Only remove expired entries")
+
+	cacheCmd.AddCommand(statsCmd, clearCmd)
+
+	return cacheCmd
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"auto-spotify/internal/spotify"
+
+	"github.com/spf13/cobra"
+)
+
+// NewDedupeCmd creates the dedupe command
+func NewDedupeCmd(spotifyService *spotify.Service) *cobra.Command {
+	var (
+		playlistName string
+		dryRun       bool
+	)
+
+	dedupeCmd := &cobra.Command{
+		Use:   "dedupe",
+		Short: "Remove duplicate tracks from an existing Spotify playlist",
+		Long: `Remove duplicate tracks from one of your Spotify playlists. A track counts as a
+duplicate when it has the same Spotify ID as an earlier track, or when it is the same
+song by the same artist released on a different album (single, remaster, compilation).
+The first occurrence of each song is kept.
+
+Examples:
+  auto-spotify dedupe --playlist "My Mix"            # Remove duplicates
+  auto-spotify dedupe --playlist "My Mix" --dry-run  # Only list duplicates`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			if playlistName == "" {
+				return fmt.Errorf("playlist name is required (use --playlist flag)")
+			}
+
+			// Authenticate with Spotify
+			fmt.Println("🎧 Connecting to Spotify...")
+			if err := spotifyService.Authenticate(ctx); err != nil {
+				return fmt.Errorf("failed to authenticate with Spotify: %w", err)
+			}
+
+			playlist, err := lookupPlaylist(ctx, spotifyService, playlistName)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("🔍 Checking '%s' for duplicates (%d tracks)...\n", playlist.Name, playlist.TrackCount)
+			duplicates, err := spotifyService.DedupePlaylist(ctx, playlist.ID, dryRun)
+			if err != nil {
+				return fmt.Errorf("failed to dedupe playlist: %w", err)
+			}
+
+			if len(duplicates) == 0 {
+				fmt.Println("✅ No duplicates found")
+				return nil
+			}
+
+			for _, track := range duplicates {
+				fmt.Printf("  ↺ #%d %s - %s\n", track.Position+1, track.Artist, track.Title)
+			}
+
+			if dryRun {
+				fmt.Printf("\n📊 Found %d duplicates (dry run, nothing removed)\n", len(duplicates))
+			} else {
+				fmt.Printf("\n🧹 Removed %d duplicates\n", len(duplicates))
+			}
+
+			return nil
+		},
+	}
+
+	dedupeCmd.Flags().StringVarP(&playlistName, "playlist", "p", "", "Name, ID, URI or URL of the playlist to clean up (required)")
+	dedupeCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only list duplicates without removing them")
+
+	return dedupeCmd
+}
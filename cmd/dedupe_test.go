@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"testing"
+
+	"auto-spotify/internal/spotify"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDedupeCmd(t *testing.T) {
+	spotifyService := spotify.NewService("test-id", "test-secret", "http://localhost:8080/callback")
+
+	dedupeCmd := NewDedupeCmd(spotifyService)
+
+	assert.NotNil(t, dedupeCmd)
+	assert.Equal(t, "dedupe", dedupeCmd.Use)
+
+	playlistFlag := dedupeCmd.Flags().Lookup("playlist")
+	assert.NotNil(t, playlistFlag)
+	assert.Equal(t, "p", playlistFlag.Shorthand)
+
+	dryRunFlag := dedupeCmd.Flags().Lookup("dry-run")
+	assert.NotNil(t, dryRunFlag)
+	assert.Equal(t, "false", dryRunFlag.DefValue)
+}
+
+func TestDedupeCmd_RequiresPlaylist(t *testing.T) {
+	spotifyService := spotify.NewService("test-id", "test-secret", "http://localhost:8080/callback")
+
+	dedupeCmd := NewDedupeCmd(spotifyService)
+	dedupeCmd.SetArgs([]string{})
+
+	err := dedupeCmd.Execute()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "playlist name is required")
+}
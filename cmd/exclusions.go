@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"auto-spotify/internal/exclusions"
+	"auto-spotify/internal/openai"
+	"auto-spotify/internal/spotify"
+
+	spotifyapi "github.com/zmb3/spotify/v2"
+)
+
+// loadExclusions combines the exclusion files with the artists, tracks and
+// playlists given as flags
+func loadExclusions(files, artists, tracks, playlists []string) (*exclusions.List, error) {
+	list := &exclusions.List{
+		Artists:   append([]string(nil), artists...),
+		Tracks:    append([]string(nil), tracks...),
+		Playlists: append([]string(nil), playlists...),
+	}
+
+	for _, file := range files {
+		fileList, err := exclusions.Load(file)
+		if err != nil {
+			return nil, err
+		}
+		list.Merge(fileList)
+	}
+
+	return list, nil
+}
+
+// resolveExclusions looks up the excluded tracks and the songs in excluded
+// playlists on Spotify
+func resolveExclusions(ctx context.Context, spotifyService *spotify.Service, list *exclusions.List) (spotify.Exclusions, error) {
+	excluded := spotify.Exclusions{Artists: list.Artists}
+
+	var ids []spotifyapi.ID
+	for _, track := range list.Tracks {
+		id, err := spotify.ParseTrackID(track)
+		if err != nil {
+			return excluded, fmt.Errorf("invalid excluded track: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) > 0 {
+		tracks, err := spotifyService.GetTracks(ctx, ids)
+		if err != nil {
+			return excluded, fmt.Errorf("failed to look up excluded tracks: %w", err)
+		}
+		excluded.Tracks = append(excluded.Tracks, tracks...)
+	}
+
+	for _, name := range list.Playlists {
+		playlist, err := lookupPlaylist(ctx, spotifyService, name)
+		if err != nil {
+			return excluded, fmt.Errorf("failed to find excluded playlist: %w", err)
+		}
+		tracks, err := spotifyService.GetPlaylistTracks(ctx, playlist.ID)
+		if err != nil {
+			return excluded, fmt.Errorf("failed to read excluded playlist '%s': %w", playlist.Name, err)
+		}
+		fmt.Printf("⛔ Excluding %d tracks already heard in '%s'\n", len(tracks), playlist.Name)
+		excluded.Tracks = append(excluded.Tracks, tracks...)
+	}
+
+	return excluded, nil
+}
+
+// avoidSongs lists the songs the AI must not suggest: the seed playlist and
+// every excluded track
+func avoidSongs(seedTracks []spotify.TrackInfo, excluded spotify.Exclusions) []openai.Song {
+	return append(tracksToSongs(seedTracks), tracksToSongs(excluded.Tracks)...)
+}
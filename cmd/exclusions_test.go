@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"auto-spotify/internal/spotify"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadExclusions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "exclude.txt")
+	require.NoError(t, os.WriteFile(path, []byte("artist: Creed\nplaylist: Heard It All\n"), 0644))
+
+	list, err := loadExclusions([]string{path}, []string{"Nickelback"}, []string{"spotify:track:4uLU6hMCjMI75M1A2tKUQC"}, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Nickelback", "Creed"}, list.Artists)
+	assert.Equal(t, []string{"spotify:track:4uLU6hMCjMI75M1A2tKUQC"}, list.Tracks)
+	assert.Equal(t, []string{"Heard It All"}, list.Playlists)
+
+	_, err = loadExclusions([]string{filepath.Join(t.TempDir(), "missing.txt")}, nil, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestResolveExclusions_ArtistsOnly(t *testing.T) {
+	// Artists don't need Spotify, so an unauthenticated service is fine
+	service := spotify.NewService("id", "secret", "http://localhost:8080/callback")
+
+	list, err := loadExclusions(nil, []string{"Nickelback"}, nil, nil)
+	require.NoError(t, err)
+
+	excluded, err := resolveExclusions(context.Background(), service, list)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Nickelback"}, excluded.Artists)
+	assert.Empty(t, excluded.Tracks)
+}
+
+func TestResolveExclusions_InvalidTrack(t *testing.T) {
+	service := spotify.NewService("id", "secret", "http://localhost:8080/callback")
+
+	list, err := loadExclusions(nil, nil, []string{"not-a-track"}, nil)
+	require.NoError(t, err)
+
+	_, err = resolveExclusions(context.Background(), service, list)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid excluded track")
+}
+
+func TestAvoidSongs(t *testing.T) {
+	seed := []spotify.TrackInfo{{Artist: "Toto", Title: "Africa"}}
+	excluded := spotify.Exclusions{Tracks: []spotify.TrackInfo{{Artist: "Rick Astley", Title: "Never Gonna Give You Up"}}}
+
+	songs := avoidSongs(seed, excluded)
+
+	require.Len(t, songs, 2)
+	assert.Equal(t, "Toto", songs[0].Artist)
+	assert.Equal(t, "Never Gonna Give You Up", songs[1].Title)
+}
@@ -3,33 +3,41 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"auto-spotify/internal/playlistfile"
 	"auto-spotify/internal/spotify"
 
 	"github.com/spf13/cobra"
 )
 
-// NewExportCmd creates the export command
-func NewExportCmd(spotifyService *spotify.Service) *cobra.Command {
+// NewExportCmd creates the export command. libraryRoot is the default music
+// library that M3U8 and XSPF exports point to.
+func NewExportCmd(spotifyService *spotify.Service, libraryRoot string) *cobra.Command {
 	var (
 		outputDir    string
 		playlistName string
 		allPlaylists bool
+		formatName   string
 	)
 
 	exportCmd := &cobra.Command{
 		Use:   "export",
-		Short: "Export Spotify playlists to text files",
-		Long: `Export your Spotify playlists to text files in the format that auto-spotify can read.
+		Short: "Export Spotify playlists to files",
+		Long: `Export your Spotify playlists to files in a format that auto-spotify can read.
 This is useful for backing up playlists or sharing them with others.
 
 Examples:
   auto-spotify export --dir ./backups                    # Export all playlists
   auto-spotify export --dir ./backups --playlist "My Mix" # Export specific playlist
-  auto-spotify export --all --dir ./exports              # Export all playlists explicitly`,
+  auto-spotify export --all --dir ./exports              # Export all playlists explicitly
+  auto-spotify export --dir ./backups --format json      # Export as JSON (or yaml) for other tools
+  auto-spotify export --dir ./sheets --format csv        # One row per track with its Spotify details
+  auto-spotify export --dir ./players --format m3u8 --library-root ~/Music  # Point to local files where possible
+  auto-spotify export --dir - --playlist "My Mix" | grep Toto  # Write the playlist to stdout`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
 
@@ -38,60 +46,105 @@ Examples:
 				return fmt.Errorf("output directory is required (use --dir flag)")
 			}
 
+			toStdout := outputDir == playlistfile.Stdin
+			if toStdout && playlistName == "" {
+				return fmt.Errorf("writing to stdout needs a single playlist (use --playlist)")
+			}
+
+			format, err := playlistfile.Lookup(formatName)
+			if err != nil {
+				return err
+			}
+			if format.Writer == nil {
+				return fmt.Errorf("exporting to %s is not supported", format.Name)
+			}
+
+			target := exportTarget{dir: outputDir, format: format, progress: os.Stdout}
+			if toStdout {
+				// Progress goes to stderr so only the playlist reaches stdout
+				target.stdout = os.Stdout
+				target.progress = os.Stderr
+			}
+			// The Spotify service's progress, like the login prompt, goes there too
+			progress := spotifyService.Progress()
+			spotifyService.SetProgress(target.progress)
+			defer spotifyService.SetProgress(progress)
+
+			if libraryRoot != "" && format.Locations {
+				target.library, err = playlistfile.OpenLibrary(libraryRoot)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(target.progress, "🎵 Found %d music files in %s\n", target.library.Len(), target.library.Root)
+			}
+
 			// Create output directory if it doesn't exist
-			if err := os.MkdirAll(outputDir, 0755); err != nil {
-				return fmt.Errorf("failed to create output directory: %w", err)
+			if !toStdout {
+				if err := os.MkdirAll(outputDir, 0755); err != nil {
+					return fmt.Errorf("failed to create output directory: %w", err)
+				}
 			}
 
 			// Authenticate with Spotify
-			fmt.Println("🎧 Connecting to Spotify...")
+			fmt.Fprintln(target.progress, "🎧 Connecting to Spotify...")
 			if err := spotifyService.Authenticate(ctx); err != nil {
 				return fmt.Errorf("failed to authenticate with Spotify: %w", err)
 			}
 
 			if playlistName != "" {
 				// Export specific playlist
-				fmt.Printf("📁 Exporting playlist: %s\n", playlistName)
-				return exportPlaylist(ctx, spotifyService, playlistName, outputDir)
+				fmt.Fprintf(target.progress, "📁 Exporting playlist: %s\n", playlistName)
+				return exportPlaylist(ctx, spotifyService, playlistName, target)
 			} else {
 				// Export all playlists
-				fmt.Println("📁 Exporting all your playlists...")
-				return exportAllPlaylists(ctx, spotifyService, outputDir)
+				fmt.Fprintln(target.progress, "📁 Exporting all your playlists...")
+				return exportAllPlaylists(ctx, spotifyService, target)
 			}
 		},
 	}
 
-	exportCmd.Flags().StringVarP(&outputDir, "dir", "d", "", "Output directory for exported playlist files (required)")
-	exportCmd.Flags().StringVarP(&playlistName, "playlist", "p", "", "Export specific playlist by name")
+	exportCmd.Flags().StringVarP(&outputDir, "dir", "d", "", "Output directory for exported playlist files, or - to write a single playlist to stdout (required)")
+	exportCmd.Flags().StringVarP(&playlistName, "playlist", "p", "", "Export specific playlist by name, ID, URI or URL")
 	exportCmd.Flags().BoolVarP(&allPlaylists, "all", "a", false, "Export all playlists (default behavior)")
+	exportCmd.Flags().StringVar(&formatName, "format", playlistfile.DefaultFormat, "File format to export to: "+strings.Join(playlistfile.Names(), ", "))
+	exportCmd.Flags().StringVar(&libraryRoot, "library-root", libraryRoot, "Music folder to point M3U8 and XSPF tracks to local files in (default from MUSIC_LIBRARY_ROOT)")
 
 	return exportCmd
 }
 
-func exportPlaylist(ctx context.Context, spotifyService *spotify.Service, playlistName, outputDir string) error {
+// exportTarget is where and how playlists are exported
+type exportTarget struct {
+	dir      string
+	stdout   io.Writer // Set to write the playlist there instead of to a file in dir
+	progress io.Writer // Where progress is printed, stderr when the playlist goes to stdout
+	format   playlistfile.Format
+	library  *playlistfile.Library // Local files tracks point to in formats with locations (optional)
+}
+
+func exportPlaylist(ctx context.Context, spotifyService *spotify.Service, playlistName string, target exportTarget) error {
 	// Get the specific playlist
-	playlists, err := spotifyService.GetUserPlaylists(ctx)
+	targetPlaylist, err := lookupPlaylist(ctx, spotifyService, playlistName)
 	if err != nil {
-		return fmt.Errorf("failed to get playlists: %w", err)
+		return err
 	}
 
-	var targetPlaylist *spotify.PlaylistInfo
-	for _, playlist := range playlists {
-		if playlist.Name == playlistName {
-			targetPlaylist = &playlist
-			break
-		}
-	}
+	// Export the playlist
+	return exportSinglePlaylist(ctx, spotifyService, *targetPlaylist, target)
+}
 
-	if targetPlaylist == nil {
-		return fmt.Errorf("playlist '%s' not found", playlistName)
+// lookupPlaylist finds a playlist by ID, URI, URL or name
+func lookupPlaylist(ctx context.Context, spotifyService *spotify.Service, ref string) (*spotify.PlaylistInfo, error) {
+	playlist, err := spotifyService.FindPlaylist(ctx, ref)
+	if err != nil {
+		return nil, err
 	}
-
-	// Export the playlist
-	return exportSinglePlaylist(ctx, spotifyService, *targetPlaylist, outputDir)
+	if playlist == nil {
+		return nil, fmt.Errorf("playlist '%s' not found", ref)
+	}
+	return playlist, nil
 }
 
-func exportAllPlaylists(ctx context.Context, spotifyService *spotify.Service, outputDir string) error {
+func exportAllPlaylists(ctx context.Context, spotifyService *spotify.Service, target exportTarget) error {
 	// Get all user playlists
 	playlists, err := spotifyService.GetUserPlaylists(ctx)
 	if err != nil {
@@ -99,73 +152,116 @@ func exportAllPlaylists(ctx context.Context, spotifyService *spotify.Service, ou
 	}
 
 	if len(playlists) == 0 {
-		fmt.Println("📭 No playlists found to export")
+		fmt.Fprintln(target.progress, "📭 No playlists found to export")
 		return nil
 	}
 
-	fmt.Printf("📋 Found %d playlists to export\n\n", len(playlists))
+	fmt.Fprintf(target.progress, "📋 Found %d playlists to export\n\n", len(playlists))
 
 	exported := 0
 	failed := 0
 
 	for _, playlist := range playlists {
-		fmt.Printf("📁 Exporting: %s (%d tracks)\n", playlist.Name, playlist.TrackCount)
+		fmt.Fprintf(target.progress, "📁 Exporting: %s (%d tracks)\n", playlist.Name, playlist.TrackCount)
 
-		if err := exportSinglePlaylist(ctx, spotifyService, playlist, outputDir); err != nil {
-			fmt.Printf("  ❌ Failed: %v\n", err)
+		if err := exportSinglePlaylist(ctx, spotifyService, playlist, target); err != nil {
+			fmt.Fprintf(target.progress, "  ❌ Failed: %v\n", err)
 			failed++
 		} else {
-			fmt.Printf("  ✅ Exported successfully\n")
+			fmt.Fprintf(target.progress, "  ✅ Exported successfully\n")
 			exported++
 		}
-		fmt.Println()
+		fmt.Fprintln(target.progress)
 	}
 
-	fmt.Printf("📊 Export Summary:\n")
-	fmt.Printf("  ✅ Successfully exported: %d playlists\n", exported)
+	fmt.Fprintf(target.progress, "📊 Export Summary:\n")
+	fmt.Fprintf(target.progress, "  ✅ Successfully exported: %d playlists\n", exported)
 	if failed > 0 {
-		fmt.Printf("  ❌ Failed to export: %d playlists\n", failed)
+		fmt.Fprintf(target.progress, "  ❌ Failed to export: %d playlists\n", failed)
 	}
 
 	return nil
 }
 
-func exportSinglePlaylist(ctx context.Context, spotifyService *spotify.Service, playlist spotify.PlaylistInfo, outputDir string) error {
+// exportSinglePlaylist writes a playlist to a file in the target directory,
+// or to the target's stdout
+func exportSinglePlaylist(ctx context.Context, spotifyService *spotify.Service, playlist spotify.PlaylistInfo, target exportTarget) error {
 	// Get playlist tracks
 	tracks, err := spotifyService.GetPlaylistTracks(ctx, playlist.ID)
 	if err != nil {
 		return fmt.Errorf("failed to get tracks for playlist '%s': %w", playlist.Name, err)
 	}
 
-	// Create filename (sanitize playlist name)
-	filename := sanitizeFilename(playlist.Name) + ".txt"
-	filepath := filepath.Join(outputDir, filename)
-
-	// Create the text file
-	file, err := os.Create(filepath)
-	if err != nil {
-		return fmt.Errorf("failed to create file '%s': %w", filepath, err)
+	market := spotifyService.Market(ctx)
+	file := exportedPlaylist(playlist, tracks, market)
+	opts := playlistfile.Options{Format: target.format.Name, Library: target.library}
+	if target.stdout != nil {
+		if err := playlistfile.Write(target.stdout, file, opts); err != nil {
+			return fmt.Errorf("failed to write playlist to stdout: %w", err)
+		}
+	} else {
+		// Create filename (sanitize playlist name)
+		path := filepath.Join(target.dir, sanitizeFilename(playlist.Name)+target.format.Extension())
+		if err := playlistfile.Save(path, file, opts); err != nil {
+			return err
+		}
 	}
-	defer file.Close()
 
-	// Write playlist header (optional, as comment)
-	fmt.Fprintf(file, "# %s\n", playlist.Name)
-	if playlist.Description != "" {
-		fmt.Fprintf(file, "# %s\n", playlist.Description)
+	if target.library != nil && target.format.Locations {
+		local := 0
+		for _, track := range file.Tracks {
+			if _, ok := target.library.Find(track); ok {
+				local++
+			}
+		}
+		fmt.Fprintf(target.progress, "  🎵 %d of %d tracks point to local files\n", local, len(file.Tracks))
 	}
-	fmt.Fprintf(file, "# %d tracks\n", len(tracks))
-	fmt.Fprintf(file, "# Exported from Spotify\n\n")
 
-	// Write tracks in the format that auto-spotify can read
+	unavailable := 0
 	for _, track := range tracks {
-		// Format: "Artist - Song Title"
-		// This matches the format expected by LoadPlaylistFromFile
-		fmt.Fprintf(file, "%s - %s\n", track.Artist, track.Title)
+		if track.Unavailable {
+			unavailable++
+		}
+	}
+	if unavailable > 0 {
+		fmt.Fprintf(target.progress, "  ⚠️  %d tracks are not available in %s\n", unavailable, market)
 	}
 
 	return nil
 }
 
+// exportedPlaylist converts a Spotify playlist and its tracks for writing to a file
+func exportedPlaylist(playlist spotify.PlaylistInfo, tracks []spotify.TrackInfo, market string) *playlistfile.Playlist {
+	public := playlist.Public
+	file := &playlistfile.Playlist{
+		Name:        playlist.Name,
+		Description: playlist.Description,
+		Public:      &public,
+		SourceID:    playlist.ID,
+		Market:      market,
+	}
+	for _, track := range tracks {
+		var uri string
+		if track.ID != "" {
+			uri = "spotify:track:" + track.ID
+		}
+		file.Tracks = append(file.Tracks, playlistfile.Track{
+			Artist:      track.Artist,
+			Title:       track.Title,
+			Album:       track.Album,
+			Year:        track.Year,
+			URI:         uri,
+			Artists:     track.Artists,
+			Duration:    track.Duration,
+			ISRC:        track.ISRC,
+			Popularity:  track.Popularity,
+			AddedAt:     track.AddedAt,
+			Unavailable: track.Unavailable,
+		})
+	}
+	return file
+}
+
 // sanitizeFilename removes or replaces characters that aren't safe for filenames
 func sanitizeFilename(name string) string {
 	// Replace problematic characters with underscores
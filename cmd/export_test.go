@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"auto-spotify/internal/spotify"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportCmd_UnknownFormat(t *testing.T) {
+	spotifyService := spotify.NewService("test-id", "test-secret", "http://localhost:8080/callback")
+
+	exportCmd := NewExportCmd(spotifyService, "")
+	exportCmd.SetArgs([]string{"--dir", t.TempDir(), "--format", "wav"})
+
+	err := exportCmd.Execute()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown playlist format 'wav'")
+}
+
+func TestExportedPlaylist(t *testing.T) {
+	playlist := spotify.PlaylistInfo{ID: "abc", Name: "My Mix", Description: "Road songs", Public: true}
+	tracks := []spotify.TrackInfo{
+		{ID: "1", Artist: "Queen", Artists: []string{"Queen"}, Title: "Bohemian Rhapsody", Album: "A Night at the Opera", Year: 1975, ISRC: "GBUM71029604", Duration: 354 * time.Second},
+		{ID: "2", Artist: "Toto", Title: "Africa", Unavailable: true},
+	}
+
+	file := exportedPlaylist(playlist, tracks, "DE")
+
+	assert.Equal(t, "My Mix", file.Name)
+	assert.Equal(t, "Road songs", file.Description)
+	assert.Equal(t, "DE", file.Market)
+	assert.Equal(t, "abc", file.SourceID)
+	require.NotNil(t, file.Public)
+	assert.True(t, *file.Public)
+	require.Len(t, file.Tracks, 2)
+	assert.Equal(t, "A Night at the Opera", file.Tracks[0].Album)
+	assert.Equal(t, 1975, file.Tracks[0].Year)
+	assert.Equal(t, "spotify:track:1", file.Tracks[0].URI)
+	assert.Equal(t, []string{"Queen"}, file.Tracks[0].Artists)
+	assert.Equal(t, "GBUM71029604", file.Tracks[0].ISRC)
+	assert.Equal(t, 354*time.Second, file.Tracks[0].Duration)
+	assert.True(t, file.Tracks[1].Unavailable)
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	assert.Equal(t, "AC_DC Hits", sanitizeFilename("AC/DC Hits"))
+	assert.Equal(t, "playlist", sanitizeFilename(" ... "))
+}
+
+func TestExportCmd_MissingLibraryRoot(t *testing.T) {
+	spotifyService := spotify.NewService("test-id", "test-secret", "http://localhost:8080/callback")
+
+	exportCmd := NewExportCmd(spotifyService, filepath.Join(t.TempDir(), "missing"))
+	exportCmd.SetArgs([]string{"--dir", t.TempDir(), "--format", "m3u8"})
+
+	err := exportCmd.Execute()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to open music library")
+}
+
+func TestExportCmd_StdoutNeedsPlaylist(t *testing.T) {
+	spotifyService := spotify.NewService("test-id", "test-secret", "http://localhost:8080/callback")
+
+	exportCmd := NewExportCmd(spotifyService, "")
+	exportCmd.SetArgs([]string{"--dir", "-"})
+
+	err := exportCmd.Execute()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "writing to stdout needs a single playlist")
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"log/slog"
+
+	"auto-spotify/internal/openai"
+	"auto-spotify/internal/playlistfile"
+	"auto-spotify/internal/registry"
+)
+
+// loadPlaylistFile reads a playlist file into the songs to search for, and
+// returns the file's content for its other settings. A non-empty name
+// replaces the name from the file. Lines that could be read in more than one
+// way and skipped songs are logged as warnings.
+func loadPlaylistFile(path string, opts playlistfile.Options, name string, logger *slog.Logger) (*openai.PlaylistResponse, *playlistfile.Playlist, error) {
+	playlist, err := playlistfile.Load(path, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logFileWarnings(logger, path, playlist.Warnings)
+
+	return playlistResponse(playlist, name), playlist, nil
+}
+
+// logFileWarnings logs the problems found while reading a playlist file
+func logFileWarnings(logger *slog.Logger, path string, warnings []playlistfile.Warning) {
+	for _, warning := range warnings {
+		if warning.Line > 0 {
+			logger.Warn("ambiguous line in playlist file", "file", path, "line", warning.Line, "problem", warning.Message)
+		} else {
+			logger.Warn("problem in playlist file", "file", path, "problem", warning.Message)
+		}
+	}
+}
+
+// playlistResponse converts a playlist read from a file into the songs to
+// search for. A non-empty name replaces the playlist's name.
+func playlistResponse(playlist *playlistfile.Playlist, name string) *openai.PlaylistResponse {
+	if name == "" {
+		name = playlist.Name
+	}
+
+	songs := make([]openai.Song, 0, len(playlist.Tracks))
+	for _, track := range playlist.Tracks {
+		songs = append(songs, openai.Song{
+			Artist: track.Artist,
+			Title:  track.Title,
+			Album:  track.Album,
+			Year:   track.Year,
+			Reason: track.Reason,
+			URI:    track.URI,
+		})
+	}
+
+	return &openai.PlaylistResponse{
+		PlaylistName: name,
+		Description:  playlist.Description,
+		Songs:        songs,
+	}
+}
+
+// fileSource returns the registry source for a playlist file: the playlist it
+// was exported from if the file names one, otherwise the file itself
+func fileSource(path string, playlist *playlistfile.Playlist) string {
+	if playlist != nil && playlist.SourceID != "" {
+		return registry.PlaylistSource(playlist.SourceID)
+	}
+	if path == playlistfile.Stdin {
+		// Piped songs have no file to recognise the playlist by
+		return ""
+	}
+	return registry.FileSource(path)
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"auto-spotify/internal/playlistfile"
+	"auto-spotify/internal/registry"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadPlaylistFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "road-trip.txt")
+	require.NoError(t, os.WriteFile(path, []byte("Queen - Bohemian Rhapsody\nToto: Africa\n"), 0644))
+
+	playlist, _, err := loadPlaylistFile(path, playlistfile.Options{}, "", slog.Default())
+	require.NoError(t, err)
+	assert.Contains(t, playlist.PlaylistName, "Road Trip")
+	assert.Contains(t, playlist.Description, "2 songs")
+	require.Len(t, playlist.Songs, 2)
+	assert.Equal(t, "Toto", playlist.Songs[1].Artist)
+	assert.Equal(t, "Africa", playlist.Songs[1].Title)
+	assert.Contains(t, playlist.Songs[1].Reason, "line 2")
+
+	playlist, _, err = loadPlaylistFile(path, playlistfile.Options{Format: "txt"}, "My Trip", slog.Default())
+	require.NoError(t, err)
+	assert.Equal(t, "My Trip", playlist.PlaylistName)
+}
+
+func TestLoadPlaylistFile_UnknownFormat(t *testing.T) {
+	_, _, err := loadPlaylistFile("songs.txt", playlistfile.Options{Format: "wav"}, "", slog.Default())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown playlist format")
+}
+
+func TestLoadPlaylistFile_LogsWarnings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mix.txt")
+	require.NoError(t, os.WriteFile(path, []byte("Queen - Bohemian Rhapsody\nAC-DC\n"), 0644))
+
+	var buf bytes.Buffer
+	logger, err := newLogger(&buf, false, false, "text")
+	require.NoError(t, err)
+
+	playlist, _, err := loadPlaylistFile(path, playlistfile.Options{}, "", logger)
+	require.NoError(t, err)
+	assert.Len(t, playlist.Songs, 2)
+	assert.Contains(t, buf.String(), "ambiguous line in playlist file")
+	assert.Contains(t, buf.String(), "line=2")
+}
+
+func TestLoadPlaylistFile_Directives(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backup.txt")
+	content := "#name: Friday Mix\n#public: true\n#source-id: 37i9dQZF1DXcBWIGoYBM5M\nToto - Africa\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	playlist, file, err := loadPlaylistFile(path, playlistfile.Options{}, "", slog.Default())
+	require.NoError(t, err)
+	assert.Equal(t, "Friday Mix", playlist.PlaylistName)
+	require.NotNil(t, file.Public)
+	assert.True(t, *file.Public)
+	assert.Equal(t, "playlist:37i9dQZF1DXcBWIGoYBM5M", fileSource(path, file))
+}
+
+func TestFileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mix.txt")
+
+	assert.Equal(t, registry.FileSource(path), fileSource(path, nil))
+	assert.Equal(t, registry.FileSource(path), fileSource(path, &playlistfile.Playlist{}))
+	assert.Empty(t, fileSource(playlistfile.Stdin, &playlistfile.Playlist{}))
+	assert.Equal(t, "playlist:abc", fileSource(playlistfile.Stdin, &playlistfile.Playlist{SourceID: "abc"}))
+}
+
+func TestLoadPlaylistFile_CSVColumns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sheet.csv")
+	require.NoError(t, os.WriteFile(path, []byte("Band,Song,Rating\nToto,Africa,5\n"), 0644))
+
+	opts := playlistfile.Options{Columns: map[string]string{"artist": "Band", "title": "Song"}}
+	playlist, _, err := loadPlaylistFile(path, opts, "", slog.Default())
+
+	require.NoError(t, err)
+	require.Len(t, playlist.Songs, 1)
+	assert.Equal(t, "Toto", playlist.Songs[0].Artist)
+	assert.Equal(t, "Africa", playlist.Songs[0].Title)
+}
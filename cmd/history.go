@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"auto-spotify/internal/history"
+	"auto-spotify/internal/spotify"
+
+	"github.com/spf13/cobra"
+)
+
+// NewHistoryCmd creates the history command
+func NewHistoryCmd(spotifyService *spotify.Service) *cobra.Command {
+	var (
+		playlistName string
+		number       int
+	)
+
+	historyCmd := &cobra.Command{
+		Use:   "history",
+		Short: "List earlier versions of a playlist",
+		Long: `Before auto-spotify replaces, appends to, dedupes or restores a playlist, it saves the
+previous track list in your user config directory (or SPOTIFY_HISTORY_PATH). List
+those versions, or show the tracks of one, and restore it with the undo command.
+
+Examples:
+  auto-spotify history --playlist "My Mix"              # List saved versions
+  auto-spotify history --playlist "My Mix" --version 3  # Show the tracks of version 3`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			if playlistName == "" {
+				return fmt.Errorf("playlist name is required (use --playlist flag)")
+			}
+			playlistHistory := spotifyService.History()
+			if playlistHistory == nil {
+				return fmt.Errorf("playlist history is disabled")
+			}
+
+			// Authenticate with Spotify
+			fmt.Println("🎧 Connecting to Spotify...")
+			if err := spotifyService.Authenticate(ctx); err != nil {
+				return fmt.Errorf("failed to authenticate with Spotify: %w", err)
+			}
+
+			playlist, err := lookupPlaylist(ctx, spotifyService, playlistName)
+			if err != nil {
+				return err
+			}
+
+			if number > 0 {
+				version, err := pickVersion(playlistHistory, playlist, number)
+				if err != nil {
+					return err
+				}
+				printVersion(version)
+				return nil
+			}
+
+			versions := playlistHistory.Versions(playlist.ID)
+			if len(versions) == 0 {
+				fmt.Printf("📭 No saved versions of '%s'\n", playlist.Name)
+				return nil
+			}
+
+			fmt.Printf("🕘 Saved versions of '%s' (newest first):\n", playlist.Name)
+			for _, version := range versions {
+				fmt.Printf("  #%-3d %s  before %-7s %3d tracks  %s\n",
+					version.Number, version.SavedAt.Format("2006-01-02 15:04"), version.Action, len(version.Tracks), version.Name)
+			}
+			return nil
+		},
+	}
+
+	historyCmd.Flags().StringVarP(&playlistName, "playlist", "p", "", "Name, ID, URI or URL of the playlist (required)")
+	historyCmd.Flags().IntVar(&number, "version", 0, "Show the tracks of this version")
+
+	return historyCmd
+}
+
+// NewUndoCmd creates the undo command
+func NewUndoCmd(spotifyService *spotify.Service) *cobra.Command {
+	var (
+		playlistName string
+		number       int
+	)
+
+	undoCmd := &cobra.Command{
+		Use:   "undo",
+		Short: "Restore an earlier version of a playlist",
+		Long: `Restore a playlist's tracks and name from a version saved before an earlier change.
+Without --version the most recent version is restored, and running undo again steps
+further back. The tracks being replaced are saved as a new version first, so an undo
+can be undone as well with --version.
+
+Examples:
+  auto-spotify undo --playlist "My Mix"              # Undo the last change
+  auto-spotify undo --playlist "My Mix" --version 3  # Restore version 3`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			if playlistName == "" {
+				return fmt.Errorf("playlist name is required (use --playlist flag)")
+			}
+			playlistHistory := spotifyService.History()
+			if playlistHistory == nil {
+				return fmt.Errorf("playlist history is disabled")
+			}
+
+			// Authenticate with Spotify
+			fmt.Println("🎧 Connecting to Spotify...")
+			if err := spotifyService.Authenticate(ctx); err != nil {
+				return fmt.Errorf("failed to authenticate with Spotify: %w", err)
+			}
+
+			playlist, err := lookupPlaylist(ctx, spotifyService, playlistName)
+			if err != nil {
+				return err
+			}
+
+			version, err := pickVersion(playlistHistory, playlist, number)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("⏪ Restoring '%s' to version #%d (%d tracks)...\n", playlist.Name, version.Number, len(version.Tracks))
+			if err := spotifyService.RestorePlaylist(ctx, playlist.ID, version); err != nil {
+				return fmt.Errorf("failed to restore playlist: %w", err)
+			}
+
+			fmt.Printf("✅ Restored version #%d from %s\n", version.Number, version.SavedAt.Format("2006-01-02 15:04"))
+			return nil
+		},
+	}
+
+	undoCmd.Flags().StringVarP(&playlistName, "playlist", "p", "", "Name, ID, URI or URL of the playlist (required)")
+	undoCmd.Flags().IntVar(&number, "version", 0, "Version to restore (default: the most recent)")
+
+	return undoCmd
+}
+
+// pickVersion returns the numbered version of the playlist, or the version
+// to undo to when number is 0: the most recent one that an earlier undo
+// hasn't restored yet, so repeated undos keep stepping back
+func pickVersion(playlistHistory *history.History, playlist *spotify.PlaylistInfo, number int) (history.Version, error) {
+	if number > 0 {
+		version, ok := playlistHistory.Version(playlist.ID, number)
+		if !ok {
+			return history.Version{}, fmt.Errorf("'%s' has no version #%d", playlist.Name, number)
+		}
+		return version, nil
+	}
+
+	versions := playlistHistory.Versions(playlist.ID)
+	if len(versions) == 0 {
+		return history.Version{}, fmt.Errorf("no saved versions of '%s' to restore", playlist.Name)
+	}
+
+	// Every undo restored the version after it, newest first
+	undone := 0
+	for _, version := range versions {
+		switch {
+		case version.Action == "undo":
+			undone++
+		case undone > 0:
+			undone--
+		default:
+			return version, nil
+		}
+	}
+	return history.Version{}, fmt.Errorf("no earlier versions of '%s' to restore (use --version to pick one)", playlist.Name)
+}
+
+// printVersion lists the tracks of a saved version
+func printVersion(version history.Version) {
+	fmt.Printf("🕘 Version #%d of '%s', saved %s before %s (%d tracks):\n",
+		version.Number, version.Name, version.SavedAt.Format("2006-01-02 15:04"), version.Action, len(version.Tracks))
+	for i, track := range version.Tracks {
+		fmt.Printf("  %d. %s - %s\n", i+1, track.Artist, track.Title)
+	}
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"auto-spotify/internal/history"
+	"auto-spotify/internal/spotify"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryCmds_RequirePlaylist(t *testing.T) {
+	spotifyService := spotify.NewService("test-id", "test-secret", "http://localhost:8080/callback")
+
+	for _, command := range []string{"history", "undo"} {
+		t.Run(command, func(t *testing.T) {
+			cmd := NewHistoryCmd(spotifyService)
+			if command == "undo" {
+				cmd = NewUndoCmd(spotifyService)
+			}
+			cmd.SetArgs([]string{})
+
+			err := cmd.Execute()
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "playlist name is required")
+		})
+	}
+}
+
+func TestHistoryCmds_Disabled(t *testing.T) {
+	spotifyService := spotify.NewService("test-id", "test-secret", "http://localhost:8080/callback")
+
+	undoCmd := NewUndoCmd(spotifyService)
+	undoCmd.SetArgs([]string{"--playlist", "My Mix"})
+
+	err := undoCmd.Execute()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "playlist history is disabled")
+}
+
+func TestPickVersion(t *testing.T) {
+	playlistHistory, err := history.Open(filepath.Join(t.TempDir(), "history.json"))
+	require.NoError(t, err)
+	playlist := &spotify.PlaylistInfo{ID: "playlist1", Name: "My Mix"}
+
+	_, err = pickVersion(playlistHistory, playlist, 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no saved versions of 'My Mix'")
+
+	playlistHistory.Add("playlist1", history.Version{Action: "update", SnapshotID: "snap1"})
+	playlistHistory.Add("playlist1", history.Version{Action: "dedupe", SnapshotID: "snap2"})
+
+	latest, err := pickVersion(playlistHistory, playlist, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, latest.Number)
+
+	first, err := pickVersion(playlistHistory, playlist, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "snap1", first.SnapshotID)
+
+	_, err = pickVersion(playlistHistory, playlist, 5)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "has no version #5")
+}
+
+func TestPickVersion_RepeatedUndo(t *testing.T) {
+	playlistHistory, err := history.Open(filepath.Join(t.TempDir(), "history.json"))
+	require.NoError(t, err)
+	playlist := &spotify.PlaylistInfo{ID: "playlist1", Name: "My Mix"}
+
+	playlistHistory.Add("playlist1", history.Version{Action: "update", SnapshotID: "snap1"})
+	playlistHistory.Add("playlist1", history.Version{Action: "dedupe", SnapshotID: "snap2"})
+
+	// The first undo restores version 2 and saves the tracks it replaced
+	first, err := pickVersion(playlistHistory, playlist, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, first.Number)
+	playlistHistory.Add("playlist1", history.Version{Action: "undo", SnapshotID: "snap3"})
+
+	// The second steps further back instead of redoing the first
+	second, err := pickVersion(playlistHistory, playlist, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, second.Number)
+	playlistHistory.Add("playlist1", history.Version{Action: "undo", SnapshotID: "snap4"})
+
+	_, err = pickVersion(playlistHistory, playlist, 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no earlier versions of 'My Mix'")
+}
@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"auto-spotify/internal/playlistfile"
+	"auto-spotify/internal/registry"
+	"auto-spotify/internal/spotify"
+
+	"github.com/spf13/cobra"
+)
+
+// NewImportLibraryCmd creates the import-library command. libraryRoot is the
+// music folder used when none is given.
+func NewImportLibraryCmd(spotifyService *spotify.Service, libraryRoot string) *cobra.Command {
+	var (
+		genres       []string
+		years        string
+		folders      []string
+		playlistName string
+		description  string
+		forceCreate  bool
+		maxTracks    int
+		public       bool
+		private      bool
+		collab       bool
+		fix          bool
+		dryRun       bool
+	)
+
+	importCmd := &cobra.Command{
+		Use:   "import-library [folder]",
+		Short: "Build a Spotify playlist from the music files in a folder",
+		Long: `Read the tags (ID3, Vorbis comments or MP4 metadata) of the music files in a
+folder and build a Spotify playlist from them, matching each file the same way
+as the songs of a --file playlist. Files without tags are read by their name,
+like "Artist - Title.mp3" or "Artist/Album/01 Title.flac".
+
+Examples:
+  auto-spotify import-library ~/Music                        # Every song in the folder
+  auto-spotify import-library --genre rock --year 1980-1989  # From MUSIC_LIBRARY_ROOT
+  auto-spotify import-library ~/Music --folder "Toto" --name "Toto on Spotify"
+  auto-spotify import-library ~/Music --genre jazz --dry-run # Only list the songs`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			logger := spotifyService.Logger()
+
+			dir := libraryRoot
+			if len(args) > 0 {
+				dir = args[0]
+			}
+			if dir == "" {
+				return fmt.Errorf("music folder is required (pass it as an argument or set MUSIC_LIBRARY_ROOT)")
+			}
+			if maxTracks < 0 {
+				return fmt.Errorf("--max-tracks must not be negative")
+			}
+
+			filter := playlistfile.LibraryFilter{Genres: genres, Folders: folders}
+			var err error
+			filter.FromYear, filter.ToYear, err = parseYearRange(years)
+			if err != nil {
+				return err
+			}
+
+			var target string
+			if spotify.IsLink(playlistName) {
+				if forceCreate {
+					return fmt.Errorf("--create needs a playlist name, not a playlist URI")
+				}
+				target, playlistName = playlistName, ""
+			}
+
+			fmt.Printf("📁 Reading music library: %s\n", dir)
+			library, err := playlistfile.OpenLibrary(dir)
+			if err != nil {
+				return err
+			}
+			for _, warning := range library.Warnings {
+				logger.Warn("reading music file by its name", "problem", warning.Message)
+			}
+
+			fromLibrary := library.Playlist(filter)
+			if filter.String() != "" {
+				fmt.Printf("🎵 %d of %d music files match %s\n\n", len(fromLibrary.Tracks), library.Len(), filter)
+			} else {
+				fmt.Printf("🎵 Found %d music files\n\n", library.Len())
+			}
+			if len(fromLibrary.Tracks) == 0 {
+				return fmt.Errorf("no music files to import from %s", library.Root)
+			}
+
+			fromLibrary.Name = libraryPlaylistName(library.Root, filter)
+			fromLibrary.Description = fmt.Sprintf("Playlist built from the music library in %s (%d songs)", library.Root, len(fromLibrary.Tracks))
+			if description != "" {
+				fromLibrary.Description = description
+			}
+			playlistResp := playlistResponse(fromLibrary, playlistName)
+
+			printPlaylist(playlistResp)
+			if dryRun {
+				fmt.Println("🔎 Dry run, not creating the playlist")
+				return nil
+			}
+
+			fmt.Println("🎧 Connecting to Spotify...")
+			if err := spotifyService.Authenticate(ctx); err != nil {
+				return fmt.Errorf("failed to authenticate with Spotify: %w", err)
+			}
+
+			applyVisibility(spotifyService, public, private, collab, nil)
+			playlistOpts := spotify.PlaylistOptions{
+				ForceCreate: forceCreate,
+				Target:      target,
+				Rename:      playlistName != "",
+				MaxTracks:   maxTracks,
+				Source:      registry.LibrarySource(library.Root, filter.String()),
+			}
+			if forceCreate {
+				fmt.Println("📝 Creating new Spotify playlist...")
+			} else {
+				fmt.Println("📝 Creating/updating Spotify playlist...")
+			}
+			playlist, searchResults, err := spotifyService.CreateOrUpdatePlaylist(ctx, playlistResp, playlistOpts)
+			if err != nil {
+				return fmt.Errorf("failed to create/update Spotify playlist: %w", err)
+			}
+
+			fmt.Printf("\n🎉 Playlist created successfully!\n")
+			fmt.Printf("📋 Playlist: %s\n", playlist.Name)
+			fmt.Printf("🔗 URL: %s\n", playlist.ExternalURLs["spotify"])
+			fmt.Println()
+
+			reportSearchResults(searchResults)
+
+			if fix {
+				if err := fixSearchResults(spotifyService, searchResults); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+
+	importCmd.Flags().StringArrayVar(&genres, "genre", nil, "Only import songs of this genre, matched by whole words so 'rock' includes 'Hard Rock' (can be used multiple times)")
+	importCmd.Flags().StringVar(&years, "year", "", "Only import songs from this year or range of years, e.g. 1986, 1980-1989, 2000- or -1979")
+	importCmd.Flags().StringArrayVar(&folders, "folder", nil, "Only import songs in this folder, relative to the library folder (can be used multiple times)")
+	importCmd.Flags().StringVarP(&playlistName, "name", "n", "", "Custom playlist name, or the URI/URL of the playlist to update (default: derived from the folder and filters)")
+	importCmd.Flags().StringVar(&description, "description", "", "Custom playlist description")
+	importCmd.Flags().BoolVarP(&forceCreate, "create", "c", false, "Force create new playlist instead of updating existing one")
+	importCmd.Flags().IntVar(&maxTracks, "max-tracks", 0, "Cap on the total number of tracks in the playlist (0 for no cap)")
+	importCmd.Flags().BoolVar(&public, "public", false, "Make the playlist public")
+	importCmd.Flags().BoolVar(&private, "private", false, "Make the playlist private (the default for new playlists)")
+	importCmd.Flags().BoolVar(&collab, "collaborative", false, "Make the playlist collaborative (private, editable by people you invite)")
+	importCmd.MarkFlagsMutuallyExclusive("public", "private", "collaborative")
+	importCmd.Flags().BoolVar(&fix, "fix", false, "Interactively correct wrong matches after the run and save them as overrides")
+	importCmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the songs that would be imported without connecting to Spotify")
+
+	return importCmd
+}
+
+// parseYearRange reads a year like "1986" or a range like "1980-1989",
+// "2000-" or "-1979". Zero means the range is open on that side.
+func parseYearRange(s string) (from, to int, err error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, 0, nil
+	}
+
+	invalid := fmt.Errorf("invalid --year %q: use a year like 1986 or a range like 1980-1989", s)
+	parse := func(part string) (int, error) {
+		if part == "" {
+			return 0, nil
+		}
+		year, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || year <= 0 {
+			return 0, invalid
+		}
+		return year, nil
+	}
+
+	start, end, isRange := strings.Cut(s, "-")
+	if from, err = parse(start); err != nil {
+		return 0, 0, err
+	}
+	if !isRange {
+		return from, from, nil
+	}
+	if to, err = parse(end); err != nil {
+		return 0, 0, err
+	}
+	if (from == 0 && to == 0) || (to > 0 && from > to) {
+		return 0, 0, invalid
+	}
+	return from, to, nil
+}
+
+// libraryPlaylistName names a playlist after the library folder and the
+// filter, like "Music: Rock, 1980-1989"
+func libraryPlaylistName(root string, filter playlistfile.LibraryFilter) string {
+	var details []string
+	for _, genre := range filter.Genres {
+		details = append(details, strings.Title(genre))
+	}
+	switch {
+	case filter.FromYear > 0 && filter.FromYear == filter.ToYear:
+		details = append(details, strconv.Itoa(filter.FromYear))
+	case filter.FromYear > 0 && filter.ToYear > 0:
+		details = append(details, fmt.Sprintf("%d-%d", filter.FromYear, filter.ToYear))
+	case filter.FromYear > 0:
+		details = append(details, fmt.Sprintf("since %d", filter.FromYear))
+	case filter.ToYear > 0:
+		details = append(details, fmt.Sprintf("until %d", filter.ToYear))
+	}
+	details = append(details, filter.Folders...)
+
+	name := playlistfile.NameFromPath(root)
+	if len(details) == 0 {
+		return name
+	}
+	return name + ": " + strings.Join(details, ", ")
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"auto-spotify/internal/playlistfile"
+	"auto-spotify/internal/spotify"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseYearRange(t *testing.T) {
+	tests := []struct {
+		input   string
+		from    int
+		to      int
+		wantErr bool
+	}{
+		{"", 0, 0, false},
+		{"1986", 1986, 1986, false},
+		{"1980-1989", 1980, 1989, false},
+		{" 1980 - 1989 ", 1980, 1989, false},
+		{"2000-", 2000, 0, false},
+		{"-1979", 0, 1979, false},
+		{"-", 0, 0, true},
+		{"1989-1980", 0, 0, true},
+		{"eighties", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			from, to, err := parseYearRange(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "invalid --year")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.from, from)
+			assert.Equal(t, tt.to, to)
+		})
+	}
+}
+
+func TestLibraryPlaylistName(t *testing.T) {
+	assert.Equal(t, "Music", libraryPlaylistName("/home/me/Music", playlistfile.LibraryFilter{}))
+	assert.Equal(t, "Music: Hard Rock, 1980-1989", libraryPlaylistName("/home/me/Music", playlistfile.LibraryFilter{Genres: []string{"hard rock"}, FromYear: 1980, ToYear: 1989}))
+	assert.Equal(t, "Music: since 2000, Toto", libraryPlaylistName("/home/me/Music", playlistfile.LibraryFilter{FromYear: 2000, Folders: []string{"Toto"}}))
+}
+
+func TestImportLibraryCmd_RequiresFolder(t *testing.T) {
+	spotifyService := spotify.NewService("test-id", "test-secret", "http://localhost:8080/callback")
+
+	importCmd := NewImportLibraryCmd(spotifyService, "")
+	importCmd.SetArgs([]string{})
+
+	err := importCmd.Execute()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "music folder is required")
+}
+
+func TestImportLibraryCmd_NoMatches(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "Toto - Africa.mp3"), nil, 0644))
+	spotifyService := spotify.NewService("test-id", "test-secret", "http://localhost:8080/callback")
+
+	importCmd := NewImportLibraryCmd(spotifyService, root)
+	importCmd.SetArgs([]string{"--genre", "jazz"})
+
+	err := importCmd.Execute()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no music files to import")
+}
+
+func TestImportLibraryCmd_DryRun(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "Toto - Africa.mp3"), nil, 0644))
+	spotifyService := spotify.NewService("test-id", "test-secret", "http://localhost:8080/callback")
+
+	importCmd := NewImportLibraryCmd(spotifyService, "")
+	importCmd.SetArgs([]string{root, "--dry-run"})
+
+	require.NoError(t, importCmd.Execute())
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// newLogger builds the logger for diagnostic messages, which are kept apart
+// from the progress output on stdout. Warnings and errors are logged by
+// default, verbose adds debug details and quiet leaves only errors.
+func newLogger(w io.Writer, verbose, quiet bool, format string) (*slog.Logger, error) {
+	level := slog.LevelWarn
+	switch {
+	case verbose:
+		level = slog.LevelDebug
+	case quiet:
+		level = slog.LevelError
+	}
+
+	switch format {
+	case "", "text":
+		return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{
+			Level: level,
+			// Timestamps only add noise next to the progress output
+			ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+				if attr.Key == slog.TimeKey && len(groups) == 0 {
+					return slog.Attr{}
+				}
+				return attr
+			},
+		})), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})), nil
+	default:
+		return nil, fmt.Errorf("invalid --log-format %q: must be text or json", format)
+	}
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLogger_Levels(t *testing.T) {
+	tests := []struct {
+		name     string
+		verbose  bool
+		quiet    bool
+		expected []string
+		missing  []string
+	}{
+		{name: "default", expected: []string{"warn message", "error message"}, missing: []string{"debug message"}},
+		{name: "verbose", verbose: true, expected: []string{"debug message", "warn message", "error message"}},
+		{name: "quiet", quiet: true, expected: []string{"error message"}, missing: []string{"debug message", "warn message"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger, err := newLogger(&buf, tt.verbose, tt.quiet, "text")
+			require.NoError(t, err)
+
+			logger.Debug("debug message")
+			logger.Warn("warn message")
+			logger.Error("error message")
+
+			for _, message := range tt.expected {
+				assert.Contains(t, buf.String(), message)
+			}
+			for _, message := range tt.missing {
+				assert.NotContains(t, buf.String(), message)
+			}
+			assert.NotContains(t, buf.String(), "time=")
+		})
+	}
+}
+
+func TestNewLogger_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := newLogger(&buf, false, false, "json")
+	require.NoError(t, err)
+
+	logger.Warn("search failed", "query", "Metallica One")
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "WARN", entry["level"])
+	assert.Equal(t, "search failed", entry["msg"])
+	assert.Equal(t, "Metallica One", entry["query"])
+}
+
+func TestNewLogger_InvalidFormat(t *testing.T) {
+	_, err := newLogger(&bytes.Buffer{}, false, false, "xml")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid --log-format")
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"auto-spotify/internal/overrides"
+	"auto-spotify/internal/spotify"
+)
+
+// fixMatches walks through the search results and lets the user pin the right
+// track (or skip the song) for any wrong match, recording corrections in the
+// overrides. It returns the number of corrections made.
+func fixMatches(in io.Reader, results []spotify.SearchResult, matchOverrides *overrides.Overrides) (int, error) {
+	if matchOverrides == nil {
+		return 0, fmt.Errorf("match overrides are disabled")
+	}
+
+	fmt.Println("🔧 Fix matches")
+	fmt.Println("For each song: press Enter to keep, paste a Spotify track URI/URL to pin it,")
+	fmt.Println("type 's' to always skip it, or 'q' to finish.")
+	fmt.Println()
+
+	reader := bufio.NewReader(in)
+	fixed := 0
+
+	for i, result := range results {
+		fmt.Printf("  %d. %s\n", i+1, result.Song)
+		switch {
+		case result.Excluded:
+			fmt.Printf("     → excluded (%s - %s)\n", result.Track.Artists[0].Name, result.Track.Name)
+		case result.Found:
+			fmt.Printf("     → %s - %s\n", result.Track.Artists[0].Name, result.Track.Name)
+		case result.Skipped:
+			fmt.Printf("     → skipped\n")
+		case result.Explicit:
+			fmt.Printf("     → filtered (explicit)\n")
+		default:
+			fmt.Printf("     → not found\n")
+		}
+
+		for {
+			fmt.Print("     Fix: ")
+			line, err := reader.ReadString('\n')
+			input := strings.TrimSpace(line)
+			if err != nil && input == "" {
+				if err == io.EOF {
+					return fixed, nil
+				}
+				return fixed, fmt.Errorf("failed to read input: %w", err)
+			}
+
+			switch strings.ToLower(input) {
+			case "":
+				// Keep the current match
+			case "q":
+				return fixed, nil
+			case "s":
+				matchOverrides.Set(result.Song.Artist, result.Song.Title, overrides.SkipTarget)
+				fixed++
+			default:
+				trackID, parseErr := spotify.ParseTrackID(input)
+				if parseErr != nil {
+					fmt.Printf("     ⚠️  %v\n", parseErr)
+					continue
+				}
+				matchOverrides.Set(result.Song.Artist, result.Song.Title, "spotify:track:"+string(trackID))
+				fixed++
+			}
+			break
+		}
+	}
+
+	return fixed, nil
+}
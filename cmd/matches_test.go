@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"auto-spotify/internal/openai"
+	"auto-spotify/internal/overrides"
+	"auto-spotify/internal/spotify"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	spotifyapi "github.com/zmb3/spotify/v2"
+)
+
+func TestFixMatches(t *testing.T) {
+	matchOverrides, err := overrides.Load(filepath.Join(t.TempDir(), "overrides.txt"))
+	require.NoError(t, err)
+
+	results := []spotify.SearchResult{
+		{
+			Song:  openai.Song{Artist: "Metallica", Title: "One"},
+			Track: testTrack("Metallica", "One"),
+			Found: true,
+		},
+		{
+			Song:  openai.Song{Artist: "Queen", Title: "Under Pressure"},
+			Track: testTrack("Queen Tribute Band", "Under Pressure"),
+			Found: true,
+		},
+		{
+			Song:  openai.Song{Artist: "Nobody", Title: "Nothing"},
+			Found: false,
+		},
+	}
+
+	// Keep the first, retry an invalid ID then pin the second, skip the third
+	input := strings.Join([]string{
+		"",
+		"not-an-id",
+		"https://open.spotify.com/track/2fuCquhmrzHpu5xcA1ci9x",
+		"s",
+	}, "\n") + "\n"
+
+	fixed, err := fixMatches(strings.NewReader(input), results, matchOverrides)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, fixed)
+
+	_, ok := matchOverrides.Lookup("Metallica", "One")
+	assert.False(t, ok)
+
+	override, ok := matchOverrides.Lookup("Queen", "Under Pressure")
+	require.True(t, ok)
+	assert.Equal(t, "spotify:track:2fuCquhmrzHpu5xcA1ci9x", override.Target)
+
+	override, ok = matchOverrides.Lookup("Nobody", "Nothing")
+	require.True(t, ok)
+	assert.True(t, override.Skip())
+}
+
+func TestFixMatches_QuitEarly(t *testing.T) {
+	matchOverrides, err := overrides.Load(filepath.Join(t.TempDir(), "overrides.txt"))
+	require.NoError(t, err)
+
+	results := []spotify.SearchResult{
+		{Song: openai.Song{Artist: "Nobody", Title: "Nothing"}},
+		{Song: openai.Song{Artist: "Nobody", Title: "Something"}},
+	}
+
+	fixed, err := fixMatches(strings.NewReader("q\n"), results, matchOverrides)
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, fixed)
+	assert.Equal(t, 0, matchOverrides.Len())
+}
+
+func TestFixMatches_Disabled(t *testing.T) {
+	_, err := fixMatches(strings.NewReader(""), nil, nil)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "match overrides are disabled")
+}
+
+func testTrack(artist, title string) *spotifyapi.FullTrack {
+	return &spotifyapi.FullTrack{
+		SimpleTrack: spotifyapi.SimpleTrack{
+			Name:    title,
+			Artists: []spotifyapi.SimpleArtist{{Name: artist}},
+		},
+	}
+}
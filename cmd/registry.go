@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+
+	"auto-spotify/internal/registry"
+	"auto-spotify/internal/spotify"
+
+	"github.com/spf13/cobra"
+)
+
+// NewRegistryCmd creates the registry command with its list and forget subcommands
+func NewRegistryCmd(playlistRegistry *registry.Registry) *cobra.Command {
+	registryCmd := &cobra.Command{
+		Use:   "registry",
+		Short: "Inspect the playlists auto-spotify created from files and prompts",
+		Long: `Auto-Spotify remembers which Spotify playlist it created from each file or set of
+prompts, so running the same command again updates that playlist even after it
+was renamed in Spotify. The registry lives in your user config directory
+(or SPOTIFY_REGISTRY_PATH).
+
+Examples:
+  auto-spotify registry list                                    # Show registered playlists
+  auto-spotify registry forget spotify:playlist:37i9dQZF1DXcBWIGoYBM5M
+  auto-spotify registry forget "file:/home/me/metal-songs.txt"`,
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List registered playlists",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if playlistRegistry == nil {
+				return fmt.Errorf("playlist registry is disabled")
+			}
+
+			entries := playlistRegistry.Entries()
+			if len(entries) == 0 {
+				fmt.Println("📭 No playlists registered yet")
+				return nil
+			}
+
+			fmt.Printf("📒 Registered playlists (%s):\n", playlistRegistry.Path())
+			for _, entry := range entries {
+				fmt.Printf("\n  📋 %s\n", entry.Name)
+				fmt.Printf("     🔗 spotify:playlist:%s\n", entry.PlaylistID)
+				fmt.Printf("     📁 %s\n", entry.Source)
+				fmt.Printf("     🕒 Created %s, updated %s\n", entry.CreatedAt.Format("2006-01-02 15:04"), entry.UpdatedAt.Format("2006-01-02 15:04"))
+			}
+			return nil
+		},
+	}
+
+	forgetCmd := &cobra.Command{
+		Use:   "forget <source or playlist>",
+		Short: "Remove a source or playlist from the registry",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if playlistRegistry == nil {
+				return fmt.Errorf("playlist registry is disabled")
+			}
+
+			key := args[0]
+			if id, err := spotify.ParsePlaylistID(key); err == nil {
+				key = string(id)
+			}
+
+			removed := playlistRegistry.Forget(key)
+			if removed == 0 {
+				return fmt.Errorf("'%s' is not in the registry", args[0])
+			}
+
+			if err := playlistRegistry.Save(); err != nil {
+				return fmt.Errorf("failed to save playlist registry: %w", err)
+			}
+
+			fmt.Printf("🧹 Removed %d registry entries\n", removed)
+			return nil
+		},
+	}
+
+	registryCmd.AddCommand(listCmd, forgetCmd)
+
+	return registryCmd
+}
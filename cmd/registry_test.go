@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"auto-spotify/internal/registry"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryCmd_Forget(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry.json")
+	playlistRegistry, err := registry.Open(path)
+	require.NoError(t, err)
+	playlistRegistry.Record("file:/songs.txt", "37i9dQZF1DXcBWIGoYBM5M", "Metal", "")
+	playlistRegistry.Record("prompt:chill", "5ABHKGoOzxkaa28ttQV9sE", "Chill", "")
+
+	registryCmd := NewRegistryCmd(playlistRegistry)
+	registryCmd.SetArgs([]string{"forget", "https://open.spotify.com/playlist/37i9dQZF1DXcBWIGoYBM5M"})
+	require.NoError(t, registryCmd.Execute())
+
+	reloaded, err := registry.Open(path)
+	require.NoError(t, err)
+	_, ok := reloaded.Lookup("file:/songs.txt")
+	assert.False(t, ok)
+	_, ok = reloaded.Lookup("prompt:chill")
+	assert.True(t, ok)
+
+	registryCmd.SetArgs([]string{"forget", "file:/songs.txt"})
+	err = registryCmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not in the registry")
+}
+
+func TestRegistryCmd_Disabled(t *testing.T) {
+	registryCmd := NewRegistryCmd(nil)
+	registryCmd.SetArgs([]string{"list"})
+
+	err := registryCmd.Execute()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "registry is disabled")
+}
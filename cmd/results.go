@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"auto-spotify/internal/openai"
+	"auto-spotify/internal/playlistfile"
+	"auto-spotify/internal/spotify"
+)
+
+// printPlaylist shows the playlist's name, description and songs before they
+// are searched for
+func printPlaylist(playlistResp *openai.PlaylistResponse) {
+	fmt.Printf("✅ Generated playlist: \"%s\"\n", playlistResp.PlaylistName)
+	fmt.Printf("📝 Description: %s\n\n", playlistResp.Description)
+
+	// Display recommended songs
+	fmt.Printf("🎼 Recommended songs (%d):\n", len(playlistResp.Songs))
+	for i, song := range playlistResp.Songs {
+		fmt.Printf("  %d. %s", i+1, song)
+		if song.Album != "" {
+			fmt.Printf(" (from %s)", song.Album)
+		}
+		if song.Year > 0 {
+			fmt.Printf(" [%d]", song.Year)
+		}
+		fmt.Println()
+		if song.Reason != "" {
+			fmt.Printf("     💭 %s\n", song.Reason)
+		}
+	}
+	fmt.Println()
+}
+
+// applyVisibility sets the visibility of the playlist from the flags, falling
+// back to the #public: directive of the playlist file, if any
+func applyVisibility(spotifyService *spotify.Service, public, private, collab bool, fromFile *playlistfile.Playlist) {
+	switch {
+	case public:
+		spotifyService.SetVisibility(spotify.VisibilityPublic)
+	case private:
+		spotifyService.SetVisibility(spotify.VisibilityPrivate)
+	case collab:
+		spotifyService.SetVisibility(spotify.VisibilityCollaborative)
+	case fromFile != nil && fromFile.Public != nil:
+		// The file's #public: directive applies unless a flag overrides it
+		if *fromFile.Public {
+			spotifyService.SetVisibility(spotify.VisibilityPublic)
+		} else {
+			spotifyService.SetVisibility(spotify.VisibilityPrivate)
+		}
+	}
+}
+
+// reportSearchResults summarizes how the songs were matched and lists the
+// songs that couldn't be added
+func reportSearchResults(searchResults []spotify.SearchResult) {
+	found := 0
+	notFound := 0
+	skipped := 0
+	duplicates := 0
+	excludedCount := 0
+	unavailable := 0
+	var filtered []spotify.SearchResult
+	trimmed := 0
+	for _, result := range searchResults {
+		if result.Excluded {
+			excludedCount++
+		} else if result.Duplicate {
+			duplicates++
+		} else if result.Trimmed {
+			trimmed++
+		} else if result.Found {
+			found++
+		} else if result.Skipped {
+			skipped++
+		} else if result.Explicit {
+			filtered = append(filtered, result)
+		} else {
+			notFound++
+			if result.Unavailable {
+				unavailable++
+			}
+		}
+	}
+
+	fmt.Printf("📊 Search Results Summary:\n")
+	fmt.Printf("  ✅ Found: %d songs\n", found)
+	if excludedCount > 0 {
+		fmt.Printf("  ⛔ Excluded: %d songs\n", excludedCount)
+	}
+	if duplicates > 0 {
+		fmt.Printf("  ↺ Duplicates skipped: %d songs\n", duplicates)
+	}
+	if trimmed > 0 {
+		fmt.Printf("  ✂️  Left out to fit the duration: %d songs\n", trimmed)
+	}
+	if skipped > 0 {
+		fmt.Printf("  ⏭️  Skipped by override: %d songs\n", skipped)
+	}
+	if len(filtered) > 0 {
+		fmt.Printf("  🚫 Filtered explicit: %d songs\n", len(filtered))
+	}
+	if notFound > 0 {
+		fmt.Printf("  ❌ Not found: %d songs\n", notFound)
+		if unavailable > 0 {
+			fmt.Printf("  🌍 Not playable in your market: %d songs\n", unavailable)
+		}
+		fmt.Println("\n🔍 Songs that couldn't be found:")
+		for _, result := range searchResults {
+			if !result.Found && !result.Skipped && !result.Explicit {
+				if result.Unavailable {
+					fmt.Printf("  - %s (not available in your market)\n", result.Query)
+				} else {
+					fmt.Printf("  - %s\n", result.Query)
+				}
+			}
+		}
+	}
+
+	if len(filtered) > 0 {
+		fmt.Println("\n🚫 Songs filtered out because only explicit versions exist:")
+		for _, result := range filtered {
+			fmt.Printf("  - %s\n", result.Song)
+		}
+	}
+}
+
+// fixSearchResults lets the user correct wrong matches and saves them as overrides
+func fixSearchResults(spotifyService *spotify.Service, searchResults []spotify.SearchResult) error {
+	fmt.Println()
+	matchOverrides := spotifyService.Overrides()
+	fixed, err := fixMatches(os.Stdin, searchResults, matchOverrides)
+	if err != nil {
+		return fmt.Errorf("failed to fix matches: %w", err)
+	}
+	if fixed > 0 {
+		if err := matchOverrides.Save(); err != nil {
+			return fmt.Errorf("failed to save match overrides: %w", err)
+		}
+		fmt.Printf("\n📌 Saved %d corrections to %s\n", fixed, matchOverrides.Path())
+		fmt.Println("   Run the same command again to apply them to the playlist.")
+	}
+	return nil
+}
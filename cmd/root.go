@@ -3,9 +3,17 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"math"
+	"os"
 	"strings"
+	"time"
 
+	"auto-spotify/internal/cover"
 	"auto-spotify/internal/openai"
+	"auto-spotify/internal/playlistfile"
+	"auto-spotify/internal/registry"
+	"auto-spotify/internal/songkey"
 	"auto-spotify/internal/spotify"
 
 	"github.com/spf13/cobra"
@@ -17,8 +25,34 @@ func NewRootCmd(openaiService *openai.Service, spotifyService *spotify.Service)
 		songCount    int
 		prompts      []string
 		inputFile    string
+		fileFormat   string
+		columns      map[string]string
 		playlistName string
 		forceCreate  bool
+		fix          bool
+		market       string
+		clean        bool
+		duration     time.Duration
+		tolerance    time.Duration
+		seedName     string
+		appendMode   bool
+		position     int
+		maxTracks    int
+		description  string
+		public       bool
+		private      bool
+		collab       bool
+		coverPath    string
+		genCover     bool
+		verbose      bool
+		quiet        bool
+		logFormat    string
+		logger       *slog.Logger
+
+		excludeFiles     []string
+		excludeArtists   []string
+		excludeTracks    []string
+		excludePlaylists []string
 	)
 
 	rootCmd := &cobra.Command{
@@ -33,10 +67,42 @@ Examples:
   auto-spotify "chill indie rock for studying" --songs 15
   auto-spotify "upbeat workout music" "electronic dance" --songs 25
   auto-spotify --file metal-songs.txt --name "My Metal Playlist"
+  auto-spotify --file metal-songs.txt --fix              # Correct wrong matches afterwards
+  auto-spotify "office party hits" --clean               # No explicit tracks
+  auto-spotify "high energy running music" --duration 45m
+  auto-spotify --seed-playlist "Friday Mix"              # More like an existing playlist
+  auto-spotify --seed-playlist "Friday Mix" --append     # Extend the seed playlist itself
+  auto-spotify --file new-finds.txt --name "My Mix" --append --max-tracks 100
+  auto-spotify --file new-finds.txt --name spotify:playlist:37i9dQZF1DXcBWIGoYBM5M --append
+  auto-spotify "summer hits" --public --description "Our summer soundtrack"
+  auto-spotify "summer hits" --generate-cover            # Render a cover from the playlist name
+  auto-spotify --file metal-songs.txt --cover cover.jpg
+  auto-spotify --file ranking.csv --columns artist=Band,title=Song
+  grep -i metal songs.txt | auto-spotify --name "Metal Only"  # Songs piped in on stdin
+  auto-spotify "office party hits" --exclude-artist Nickelback --exclude-playlist "Heard It All"
   auto-spotify export --dir ./backups                    # Export all playlists
-  auto-spotify export --dir ./backups --playlist "My Mix" # Export specific playlist`,
+  auto-spotify export --dir ./backups --playlist "My Mix" # Export specific playlist
+  auto-spotify dedupe --playlist "My Mix"                # Remove duplicate tracks
+  auto-spotify import-library ~/Music --genre rock       # Playlist from your music files
+  auto-spotify sync ./playlists                          # Build or update every playlist file in a folder`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			logger, err = newLogger(os.Stderr, verbose, quiet, logFormat)
+			if err != nil {
+				return err
+			}
+			spotifyService.SetLogger(logger)
+			if openaiService != nil {
+				openaiService.SetLogger(logger)
+			}
+
+			if market != "" {
+				spotifyService.SetMarket(market)
+			}
+			return nil
+		},
 		Args: func(cmd *cobra.Command, args []string) error {
-			if inputFile == "" && len(args) == 0 && len(prompts) == 0 {
+			if inputFile == "" && seedName == "" && len(args) == 0 && len(prompts) == 0 && !stdinIsPiped() {
 				return fmt.Errorf("provide either prompts or use --file to load from a text file")
 			}
 			return nil
@@ -44,12 +110,98 @@ Examples:
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
 			var playlistResp *openai.PlaylistResponse
+			var fromFile *playlistfile.Playlist
+			var seedTracks []spotify.TrackInfo
 			var err error
 
+			// Without anything else to build from, songs are piped in
+			if inputFile == "" && seedName == "" && len(args) == 0 && len(prompts) == 0 {
+				inputFile = playlistfile.Stdin
+			}
+
+			if seedName != "" && inputFile != "" {
+				return fmt.Errorf("--seed-playlist can't be combined with --file")
+			}
+			if appendMode && forceCreate {
+				return fmt.Errorf("--append can't be combined with --create")
+			}
+			if fix && inputFile == playlistfile.Stdin {
+				return fmt.Errorf("--fix reads corrections from stdin, so it can't be combined with a playlist on stdin")
+			}
+			if position < 0 || maxTracks < 0 {
+				return fmt.Errorf("--position and --max-tracks must not be negative")
+			}
+
+			// A playlist URI or URL picks the playlist to update instead of naming it
+			var target string
+			if spotify.IsLink(playlistName) {
+				if forceCreate {
+					return fmt.Errorf("--create needs a playlist name, not a playlist URI")
+				}
+				target, playlistName = playlistName, ""
+			}
+
+			// Read the cover up front so a bad file fails before any work is done
+			var coverImage []byte
+			if coverPath != "" {
+				coverImage, err = cover.Load(coverPath)
+				if err != nil {
+					return err
+				}
+			}
+
+			exclusionList, err := loadExclusions(excludeFiles, excludeArtists, excludeTracks, excludePlaylists)
+			if err != nil {
+				return err
+			}
+
+			// The seed playlist and excluded tracks have to be read before asking the AI
+			authenticated := false
+			if seedName != "" || len(exclusionList.Tracks) > 0 || len(exclusionList.Playlists) > 0 {
+				fmt.Println("🎧 Connecting to Spotify...")
+				if err := spotifyService.Authenticate(ctx); err != nil {
+					return fmt.Errorf("failed to authenticate with Spotify: %w", err)
+				}
+				authenticated = true
+			}
+
+			if seedName != "" {
+				seed, err := lookupPlaylist(ctx, spotifyService, seedName)
+				if err != nil {
+					return fmt.Errorf("failed to find seed playlist: %w", err)
+				}
+				seedTracks, err = spotifyService.GetPlaylistTracks(ctx, seed.ID)
+				if err != nil {
+					return fmt.Errorf("failed to read seed playlist: %w", err)
+				}
+				if len(seedTracks) == 0 {
+					return fmt.Errorf("seed playlist '%s' has no tracks", seed.Name)
+				}
+				fmt.Printf("🌱 Using %d tracks from '%s' as a seed\n\n", len(seedTracks), seed.Name)
+
+				// Appending without a name extends the seed playlist itself
+				if appendMode && playlistName == "" && target == "" {
+					playlistName = seed.Name
+					target = seed.ID
+				}
+			}
+
+			excluded, err := resolveExclusions(ctx, spotifyService, exclusionList)
+			if err != nil {
+				return err
+			}
+			if len(excluded.Artists) > 0 {
+				fmt.Printf("⛔ Excluding artists: %s\n", strings.Join(excluded.Artists, ", "))
+			}
+
 			if inputFile != "" {
 				// Load playlist from file
-				fmt.Printf("📁 Loading playlist from file: %s\n\n", inputFile)
-				playlistResp, err = openaiService.LoadPlaylistFromFile(inputFile, playlistName)
+				if inputFile == playlistfile.Stdin {
+					fmt.Printf("📁 Loading playlist from stdin\n\n")
+				} else {
+					fmt.Printf("📁 Loading playlist from file: %s\n\n", inputFile)
+				}
+				playlistResp, fromFile, err = loadPlaylistFile(inputFile, playlistfile.Options{Format: fileFormat, Columns: columns}, playlistName, logger)
 				if err != nil {
 					return fmt.Errorf("failed to load playlist from file: %w", err)
 				}
@@ -63,6 +215,9 @@ Examples:
 				if len(prompts) == 0 {
 					prompts = args
 				}
+				if len(prompts) == 0 && seedName != "" {
+					prompts = []string{fmt.Sprintf("More songs like my playlist \"%s\"", seedName)}
+				}
 
 				fmt.Printf("🎵 Generating playlist for prompts:\n")
 				for i, prompt := range prompts {
@@ -73,79 +228,125 @@ Examples:
 				// Generate playlist using OpenAI
 				fmt.Println("🤖 Asking ChatGPT for song recommendations...")
 
-				if len(prompts) == 1 {
-					playlistResp, err = openaiService.GeneratePlaylist(ctx, prompts[0], songCount)
-				} else {
-					playlistResp, err = openaiService.GeneratePlaylistFromMultiplePrompts(ctx, prompts, songCount)
+				seedSongs := tracksToSongs(seedTracks)
+				generateOpts := openai.GenerateOptions{
+					SongCount:    songCount,
+					Clean:        clean,
+					SeedSongs:    seedSongs,
+					AvoidSongs:   avoidSongs(seedTracks, excluded),
+					AvoidArtists: excluded.Artists,
+				}
+				if duration > 0 {
+					// Ask for extra candidates so the playlist can be trimmed to length
+					generateOpts.SongCount = songsForDuration(duration)
+					generateOpts.TargetDuration = duration
 				}
+				playlistResp, err = openaiService.GeneratePlaylistWithOptions(ctx, prompts, generateOpts)
 
 				if err != nil {
 					return fmt.Errorf("failed to generate playlist: %w", err)
 				}
-			}
 
-			fmt.Printf("✅ Generated playlist: \"%s\"\n", playlistResp.PlaylistName)
-			fmt.Printf("📝 Description: %s\n\n", playlistResp.Description)
-
-			// Display recommended songs
-			fmt.Printf("🎼 Recommended songs (%d):\n", len(playlistResp.Songs))
-			for i, song := range playlistResp.Songs {
-				fmt.Printf("  %d. %s - %s", i+1, song.Artist, song.Title)
-				if song.Album != "" {
-					fmt.Printf(" (from %s)", song.Album)
-				}
-				if song.Year > 0 {
-					fmt.Printf(" [%d]", song.Year)
+				if playlistName != "" {
+					playlistResp.PlaylistName = playlistName
 				}
-				fmt.Println()
-				if song.Reason != "" {
-					fmt.Printf("     💭 %s\n", song.Reason)
+
+				// The AI doesn't always listen, drop anything already in the seed
+				if len(seedTracks) > 0 {
+					var removed []openai.Song
+					playlistResp.Songs, removed = excludeSongs(playlistResp.Songs, seedSongs)
+					if len(removed) > 0 {
+						fmt.Printf("🌱 Dropped %d suggestions already in the seed playlist\n", len(removed))
+					}
 				}
 			}
-			fmt.Println()
+
+			if description != "" {
+				playlistResp.Description = description
+			}
+
+			printPlaylist(playlistResp)
 
 			// Authenticate with Spotify
-			fmt.Println("🎧 Connecting to Spotify...")
-			if err := spotifyService.Authenticate(ctx); err != nil {
-				return fmt.Errorf("failed to authenticate with Spotify: %w", err)
+			if !authenticated {
+				fmt.Println("🎧 Connecting to Spotify...")
+				if err := spotifyService.Authenticate(ctx); err != nil {
+					return fmt.Errorf("failed to authenticate with Spotify: %w", err)
+				}
 			}
 
 			// Create or update playlist on Spotify
+			spotifyService.SetClean(clean)
+			applyVisibility(spotifyService, public, private, collab, fromFile)
+			playlistOpts := spotify.PlaylistOptions{
+				ForceCreate:       forceCreate,
+				Target:            target,
+				Rename:            playlistName != "",
+				TargetDuration:    duration,
+				DurationTolerance: tolerance,
+				SkipTracks:        seedTracks,
+				Exclusions:        excluded,
+				Append:            appendMode,
+				InsertPosition:    position,
+				MaxTracks:         maxTracks,
+				Cover:             coverImage,
+			}
+			if genCover {
+				playlistOpts.Cover, err = cover.Generate(playlistResp.PlaylistName)
+				if err != nil {
+					logger.Warn("failed to generate cover image", "error", err)
+				}
+			}
+			if inputFile != "" {
+				playlistOpts.Source = fileSource(inputFile, fromFile)
+			} else {
+				playlistOpts.Source = registry.PromptSource(prompts)
+			}
+			if duration > 0 && inputFile == "" {
+				playlistOpts.TopUp = func(ctx context.Context, have []openai.Song, missing time.Duration) ([]openai.Song, error) {
+					more, err := openaiService.GeneratePlaylistWithOptions(ctx, prompts, openai.GenerateOptions{
+						SongCount:      songsForDuration(missing),
+						Clean:          clean,
+						TargetDuration: missing,
+						AvoidSongs:     append(avoidSongs(seedTracks, excluded), have...),
+						AvoidArtists:   excluded.Artists,
+					})
+					if err != nil {
+						return nil, err
+					}
+					return more.Songs, nil
+				}
+			}
 			if forceCreate {
 				fmt.Println("📝 Creating new Spotify playlist...")
+			} else if appendMode {
+				fmt.Println("📝 Appending to Spotify playlist...")
 			} else {
 				fmt.Println("📝 Creating/updating Spotify playlist...")
 			}
-			playlist, searchResults, err := spotifyService.CreateOrUpdatePlaylist(ctx, playlistResp, forceCreate)
+			playlist, searchResults, err := spotifyService.CreateOrUpdatePlaylist(ctx, playlistResp, playlistOpts)
 			if err != nil {
 				return fmt.Errorf("failed to create/update Spotify playlist: %w", err)
 			}
 
 			// Report results
-			fmt.Printf("\n🎉 Playlist created successfully!\n")
+			if appendMode {
+				fmt.Printf("\n🎉 Playlist updated successfully!\n")
+			} else {
+				fmt.Printf("\n🎉 Playlist created successfully!\n")
+			}
 			fmt.Printf("📋 Playlist: %s\n", playlist.Name)
-			fmt.Printf("🔗 URL: %s\n\n", playlist.ExternalURLs["spotify"])
-
-			// Show search results summary
-			found := 0
-			notFound := 0
-			for _, result := range searchResults {
-				if result.Found {
-					found++
-				} else {
-					notFound++
-				}
+			fmt.Printf("🔗 URL: %s\n", playlist.ExternalURLs["spotify"])
+			if duration > 0 {
+				fmt.Printf("⏱️  Length: %s (target %s)\n", spotify.FormatDuration(spotify.TotalDuration(searchResults)), spotify.FormatDuration(duration))
 			}
+			fmt.Println()
 
-			fmt.Printf("📊 Search Results Summary:\n")
-			fmt.Printf("  ✅ Found: %d songs\n", found)
-			if notFound > 0 {
-				fmt.Printf("  ❌ Not found: %d songs\n", notFound)
-				fmt.Println("\n🔍 Songs that couldn't be found:")
-				for _, result := range searchResults {
-					if !result.Found {
-						fmt.Printf("  - %s\n", result.Query)
-					}
+			reportSearchResults(searchResults)
+
+			if fix {
+				if err := fixSearchResults(spotifyService, searchResults); err != nil {
+					return err
 				}
 			}
 
@@ -155,13 +356,88 @@ Examples:
 
 	rootCmd.Flags().IntVarP(&songCount, "songs", "s", 20, "Number of songs to include in the playlist (ignored when using --file)")
 	rootCmd.Flags().StringArrayVarP(&prompts, "prompt", "p", []string{}, "Additional prompts (can be used multiple times)")
-	rootCmd.Flags().StringVarP(&inputFile, "file", "f", "", "Load songs from a text file instead of using AI")
-	rootCmd.Flags().StringVarP(&playlistName, "name", "n", "", "Custom playlist name (when using --file)")
+	rootCmd.Flags().StringVarP(&inputFile, "file", "f", "", "Load songs from a playlist file instead of using AI, or - to read them from stdin")
+	rootCmd.Flags().StringVar(&fileFormat, "format", "", "Format of the --file playlist: "+strings.Join(playlistfile.Names(), ", ")+" (default: detected from the file extension)")
+	rootCmd.Flags().StringToStringVar(&columns, "columns", nil, "Map song fields to CSV/TSV columns by header or 1-based index, e.g. artist=Band,title=Song")
+	rootCmd.Flags().StringVarP(&playlistName, "name", "n", "", "Custom playlist name, or the URI/URL of the playlist to update (default: derived from the file, or chosen by the AI)")
 	rootCmd.Flags().BoolVarP(&forceCreate, "create", "c", false, "Force create new playlist instead of updating existing one")
+	rootCmd.PersistentFlags().StringVar(&market, "market", "", "Country code (e.g. US, DE) to search and fetch tracks in (default: your account's country)")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Log diagnostic details such as search queries and API calls to stderr")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only log errors to stderr, hiding warnings")
+	rootCmd.MarkFlagsMutuallyExclusive("verbose", "quiet")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "Format of the diagnostic log on stderr: text or json")
+	rootCmd.Flags().BoolVar(&clean, "clean", false, "Reject explicit tracks, preferring clean versions, and ask the AI to avoid explicit songs")
+	rootCmd.Flags().DurationVar(&duration, "duration", 0, "Target total playlist length, e.g. 45m or 1h30m (overrides --songs)")
+	rootCmd.Flags().DurationVar(&tolerance, "duration-tolerance", spotify.DefaultDurationTolerance, "How far the playlist length may be from --duration")
+	rootCmd.Flags().StringVar(&seedName, "seed-playlist", "", "Generate songs like an existing playlist (by name, ID, URI or URL), excluding songs already in it")
+	rootCmd.Flags().BoolVar(&appendMode, "append", false, "Add songs to the existing playlist instead of replacing its tracks, skipping songs already in it")
+	rootCmd.Flags().IntVar(&position, "position", 0, "With --append, 1-based position to insert the new songs at (default: end of playlist)")
+	rootCmd.Flags().IntVar(&maxTracks, "max-tracks", 0, "Cap on the total number of tracks in the playlist (0 for no cap)")
+	rootCmd.Flags().StringVar(&description, "description", "", "Custom playlist description (default: derived from the file, or written by the AI)")
+	rootCmd.Flags().BoolVar(&public, "public", false, "Make the playlist public")
+	rootCmd.Flags().BoolVar(&private, "private", false, "Make the playlist private (the default for new playlists)")
+	rootCmd.Flags().BoolVar(&collab, "collaborative", false, "Make the playlist collaborative (private, editable by people you invite)")
+	rootCmd.MarkFlagsMutuallyExclusive("public", "private", "collaborative")
+	rootCmd.Flags().StringVar(&coverPath, "cover", "", "Upload a local JPEG as the playlist cover (shrunk to fit Spotify's 256 KB limit)")
+	rootCmd.Flags().BoolVar(&genCover, "generate-cover", false, "Render a cover from the playlist name and upload it")
+	rootCmd.MarkFlagsMutuallyExclusive("cover", "generate-cover")
+	rootCmd.Flags().StringArrayVar(&excludeArtists, "exclude-artist", nil, "Never add songs by this artist (can be used multiple times)")
+	rootCmd.Flags().StringArrayVar(&excludeTracks, "exclude-track", nil, "Never add this track, as a Spotify URI, URL or ID (can be used multiple times)")
+	rootCmd.Flags().StringArrayVar(&excludePlaylists, "exclude-playlist", nil, "Treat songs in this playlist (by name, ID, URI or URL) as already heard and never add them (can be used multiple times)")
+	rootCmd.Flags().StringArrayVar(&excludeFiles, "exclude-file", nil, "Load exclusions from a file with 'artist:', 'track:' and 'playlist:' lines (can be used multiple times)")
+	rootCmd.Flags().BoolVar(&fix, "fix", false, "Interactively correct wrong matches after the run and save them as overrides")
 
 	return rootCmd
 }
 
+// stdinIsPiped reports whether stdin is a pipe or file rather than a
+// terminal. It's a variable so tests can replace it.
+var stdinIsPiped = func() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
+}
+
+// songsForDuration estimates how many songs to ask for to fill the given
+// runtime, with some headroom for songs that can't be found or get trimmed
+func songsForDuration(d time.Duration) int {
+	const averageSongLength = 3*time.Minute + 30*time.Second
+
+	count := int(math.Ceil(float64(d) / float64(averageSongLength)))
+	return count + int(math.Max(3, math.Ceil(float64(count)*0.25)))
+}
+
+// tracksToSongs converts Spotify tracks into songs for the AI prompt
+func tracksToSongs(tracks []spotify.TrackInfo) []openai.Song {
+	var songs []openai.Song
+	for _, track := range tracks {
+		songs = append(songs, openai.Song{
+			Artist: track.Artist,
+			Title:  track.Title,
+			Album:  track.Album,
+			Year:   track.Year,
+		})
+	}
+	return songs
+}
+
+// excludeSongs splits songs into those not in exclude and those that are,
+// comparing normalized artist/title
+func excludeSongs(songs, exclude []openai.Song) (kept, removed []openai.Song) {
+	excluded := make(map[string]bool)
+	for _, song := range exclude {
+		excluded[songkey.Key(song.Artist, song.Title)] = true
+	}
+
+	for _, song := range songs {
+		if excluded[songkey.Key(song.Artist, song.Title)] {
+			removed = append(removed, song)
+		} else {
+			kept = append(kept, song)
+		}
+	}
+	return kept, removed
+}
+
 // NewGenerateCmd creates a generate command (alternative interface)
 func NewGenerateCmd(openaiService *openai.Service, spotifyService *spotify.Service) *cobra.Command {
 	var (
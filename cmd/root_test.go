@@ -1,13 +1,17 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"auto-spotify/internal/openai"
 	"auto-spotify/internal/spotify"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewRootCmd(t *testing.T) {
@@ -42,6 +46,38 @@ func TestNewRootCmd(t *testing.T) {
 	createFlag := rootCmd.Flags().Lookup("create")
 	assert.NotNil(t, createFlag)
 	assert.Equal(t, "c", createFlag.Shorthand)
+
+	marketFlag := rootCmd.PersistentFlags().Lookup("market")
+	assert.NotNil(t, marketFlag)
+	assert.Equal(t, "", marketFlag.DefValue)
+
+	cleanFlag := rootCmd.Flags().Lookup("clean")
+	assert.NotNil(t, cleanFlag)
+	assert.Equal(t, "false", cleanFlag.DefValue)
+
+	durationFlag := rootCmd.Flags().Lookup("duration")
+	assert.NotNil(t, durationFlag)
+	assert.Equal(t, "0s", durationFlag.DefValue)
+
+	toleranceFlag := rootCmd.Flags().Lookup("duration-tolerance")
+	assert.NotNil(t, toleranceFlag)
+	assert.Equal(t, "2m0s", toleranceFlag.DefValue)
+
+	appendFlag := rootCmd.Flags().Lookup("append")
+	assert.NotNil(t, appendFlag)
+	assert.Equal(t, "false", appendFlag.DefValue)
+
+	positionFlag := rootCmd.Flags().Lookup("position")
+	assert.NotNil(t, positionFlag)
+	assert.Equal(t, "0", positionFlag.DefValue)
+
+	maxTracksFlag := rootCmd.Flags().Lookup("max-tracks")
+	assert.NotNil(t, maxTracksFlag)
+	assert.Equal(t, "0", maxTracksFlag.DefValue)
+
+	fixFlag := rootCmd.Flags().Lookup("fix")
+	assert.NotNil(t, fixFlag)
+	assert.Equal(t, "false", fixFlag.DefValue)
 }
 
 func TestRootCmd_ValidationErrors(t *testing.T) {
@@ -152,6 +188,95 @@ func TestRootCmd_FlagDefaults(t *testing.T) {
 	assert.Equal(t, "false", createFlag.DefValue)
 }
 
+func TestSongsForDuration(t *testing.T) {
+	// 45 minutes is about 13 songs, plus headroom
+	assert.Equal(t, 17, songsForDuration(45*time.Minute))
+	// Short targets still get a few spare songs
+	assert.Equal(t, 4, songsForDuration(3*time.Minute))
+}
+
+func TestRootCmd_AppendConflictsWithCreate(t *testing.T) {
+	openaiService := openai.NewService("test-key")
+	spotifyService := spotify.NewService("test-id", "test-secret", "http://localhost:8080/callback")
+
+	rootCmd := NewRootCmd(openaiService, spotifyService)
+	rootCmd.SetArgs([]string{"rock music", "--append", "--create"})
+
+	err := rootCmd.Execute()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "--append can't be combined with --create")
+}
+
+func TestRootCmd_CreateNeedsName(t *testing.T) {
+	openaiService := openai.NewService("test-key")
+	spotifyService := spotify.NewService("test-id", "test-secret", "http://localhost:8080/callback")
+
+	rootCmd := NewRootCmd(openaiService, spotifyService)
+	rootCmd.SetArgs([]string{"rock music", "--create", "--name", "spotify:playlist:37i9dQZF1DXcBWIGoYBM5M"})
+
+	err := rootCmd.Execute()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "--create needs a playlist name")
+}
+
+func TestRootCmd_VisibilityFlagsExclusive(t *testing.T) {
+	openaiService := openai.NewService("test-key")
+	spotifyService := spotify.NewService("test-id", "test-secret", "http://localhost:8080/callback")
+
+	rootCmd := NewRootCmd(openaiService, spotifyService)
+	rootCmd.SetArgs([]string{"rock music", "--public", "--collaborative"})
+
+	err := rootCmd.Execute()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "none of the others can be")
+}
+
+func TestRootCmd_InvalidCover(t *testing.T) {
+	openaiService := openai.NewService("test-key")
+	spotifyService := spotify.NewService("test-id", "test-secret", "http://localhost:8080/callback")
+
+	path := filepath.Join(t.TempDir(), "cover.jpg")
+	require.NoError(t, os.WriteFile(path, []byte("not an image"), 0644))
+
+	rootCmd := NewRootCmd(openaiService, spotifyService)
+	rootCmd.SetArgs([]string{"rock music", "--cover", path})
+
+	err := rootCmd.Execute()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be a JPEG")
+}
+
+func TestExcludeSongs(t *testing.T) {
+	songs := []openai.Song{
+		{Artist: "Metallica", Title: "One"},
+		{Artist: "Metallica", Title: "Battery"},
+		{Artist: "Queen", Title: "Bohemian Rhapsody - Remastered 2011"},
+	}
+	exclude := []openai.Song{
+		{Artist: "metallica", Title: "ONE"},
+		{Artist: "Queen", Title: "Bohemian Rhapsody"},
+	}
+
+	kept, removed := excludeSongs(songs, exclude)
+
+	assert.Equal(t, []openai.Song{{Artist: "Metallica", Title: "Battery"}}, kept)
+	assert.Len(t, removed, 2)
+}
+
+func TestTracksToSongs(t *testing.T) {
+	tracks := []spotify.TrackInfo{
+		{ID: "1", Artist: "Metallica", Title: "One", Album: "...And Justice for All", Year: 1988},
+	}
+
+	songs := tracksToSongs(tracks)
+
+	assert.Equal(t, []openai.Song{{Artist: "Metallica", Title: "One", Album: "...And Justice for All", Year: 1988}}, songs)
+}
+
 func TestRootCmd_Usage(t *testing.T) {
 	openaiService := openai.NewService("test-key")
 	spotifyService := spotify.NewService("test-id", "test-secret", "http://localhost:8080/callback")
@@ -170,6 +295,10 @@ func TestRootCmd_ArgumentValidation(t *testing.T) {
 
 	rootCmd := NewRootCmd(openaiService, spotifyService)
 
+	piped := false
+	defer func(original func() bool) { stdinIsPiped = original }(stdinIsPiped)
+	stdinIsPiped = func() bool { return piped }
+
 	// Test that Args function is set
 	assert.NotNil(t, rootCmd.Args)
 
@@ -181,4 +310,27 @@ func TestRootCmd_ArgumentValidation(t *testing.T) {
 	err = rootCmd.Args(rootCmd, []string{})
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "provide either prompts or use --file")
+
+	// Songs piped in on stdin are enough on their own
+	piped = true
+	err = rootCmd.Args(rootCmd, []string{})
+	assert.NoError(t, err)
+	piped = false
+
+	// A seed playlist is enough on its own
+	require.NoError(t, rootCmd.Flags().Set("seed-playlist", "Friday Mix"))
+	err = rootCmd.Args(rootCmd, []string{})
+	assert.NoError(t, err)
+}
+
+func TestRootCmd_FixConflictsWithStdin(t *testing.T) {
+	spotifyService := spotify.NewService("test-id", "test-secret", "http://localhost:8080/callback")
+
+	rootCmd := NewRootCmd(nil, spotifyService)
+	rootCmd.SetArgs([]string{"--file", "-", "--fix"})
+
+	err := rootCmd.Execute()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--fix reads corrections from stdin")
 }
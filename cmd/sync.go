@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"auto-spotify/internal/manifest"
+	"auto-spotify/internal/openai"
+	"auto-spotify/internal/playlistfile"
+	"auto-spotify/internal/registry"
+	"auto-spotify/internal/spotify"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultSongCount is the number of songs asked for when a manifest entry
+// with prompts doesn't set one, the same as the --songs default
+const defaultSongCount = 20
+
+// NewSyncCmd creates the sync command
+func NewSyncCmd(openaiService *openai.Service, spotifyService *spotify.Service) *cobra.Command {
+	var (
+		dryRun bool
+		clean  bool
+	)
+
+	syncCmd := &cobra.Command{
+		Use:   "sync <directory|manifest>",
+		Short: "Build or update many playlists from a directory or manifest",
+		Long: `Build or update a Spotify playlist for every playlist file in a directory, or
+for every playlist listed in a YAML manifest file. Playlists built before are
+updated in place, found through the playlist registry. A playlist that fails
+doesn't stop the others; the failures are listed in the report at the end.
+
+Manifest files list playlists built from a file or from prompts, with
+defaults for all of them:
+
+  defaults:
+    visibility: private
+    max_tracks: 100
+  playlists:
+    - file: metal-songs.txt
+      name: My Metal Playlist
+    - prompts: ["chill indie rock for studying"]
+      songs: 15
+      clean: true
+
+Examples:
+  auto-spotify sync ./playlists              # Every playlist file in the folder
+  auto-spotify sync playlists.yaml           # The playlists in the manifest
+  auto-spotify sync playlists.yaml --dry-run # Only check the files
+  auto-spotify sync ./playlists --clean      # No explicit tracks in any playlist`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			logger := spotifyService.Logger()
+
+			m, err := manifest.Open(args[0])
+			if err != nil {
+				return err
+			}
+			for _, entry := range m.Entries {
+				if _, err := spotify.ParseVisibility(entry.Visibility); err != nil {
+					return fmt.Errorf("%s: %w", entry.Source(), err)
+				}
+			}
+			fmt.Printf("📋 Found %d playlists to sync in %s\n\n", len(m.Entries), m.Path)
+
+			if !dryRun {
+				fmt.Println("🎧 Connecting to Spotify...")
+				if err := spotifyService.Authenticate(ctx); err != nil {
+					return fmt.Errorf("failed to authenticate with Spotify: %w", err)
+				}
+				fmt.Println()
+			}
+
+			// Entries without a visibility get the configured one, and
+			// entries without clean get --clean
+			visibility := spotifyService.Visibility()
+			defer spotifyService.SetVisibility(visibility)
+			defer spotifyService.SetClean(spotifyService.Clean())
+
+			var report syncReport
+			for i, entry := range m.Entries {
+				fmt.Printf("🔄 [%d/%d] %s\n", i+1, len(m.Entries), entry.Source())
+				if dryRun && len(entry.Prompts) > 0 {
+					fmt.Printf("  ⏭️  Not asking the AI in a dry run\n\n")
+					report.synced = append(report.synced, syncResult{name: entry.Name})
+					continue
+				}
+				spotifyService.SetVisibility(visibility)
+				spotifyService.SetClean(clean || entry.Clean)
+
+				result, err := syncEntry(ctx, openaiService, spotifyService, entry, dryRun, logger)
+				if err != nil {
+					fmt.Printf("  ❌ Failed: %v\n\n", err)
+					report.failures = append(report.failures, syncFailure{source: entry.Source(), err: err})
+					continue
+				}
+
+				if dryRun {
+					fmt.Printf("  ✅ %s: %d songs\n\n", result.name, result.songs)
+				} else {
+					fmt.Printf("  ✅ %s: %d songs added, %d not found\n", result.name, result.added, result.notFound)
+					fmt.Printf("  🔗 %s\n\n", result.url)
+				}
+				report.synced = append(report.synced, result)
+			}
+
+			report.print(dryRun)
+			if len(report.failures) > 0 {
+				return fmt.Errorf("%d of %d playlists failed to sync", len(report.failures), len(m.Entries))
+			}
+			return nil
+		},
+	}
+
+	syncCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Load the playlist files without connecting to Spotify or asking the AI")
+	syncCmd.Flags().BoolVar(&clean, "clean", false, "Reject explicit tracks in every playlist, as if each set clean: true")
+
+	return syncCmd
+}
+
+// syncResult is a playlist built by sync
+type syncResult struct {
+	name     string
+	url      string
+	songs    int // Songs loaded or generated
+	added    int
+	notFound int
+}
+
+// syncFailure is a playlist sync couldn't build
+type syncFailure struct {
+	source string
+	err    error
+}
+
+// syncReport collects the outcome of every playlist in a sync
+type syncReport struct {
+	synced   []syncResult
+	failures []syncFailure
+}
+
+func (r syncReport) print(dryRun bool) {
+	fmt.Printf("📊 Sync Summary:\n")
+	if dryRun {
+		songs := 0
+		for _, result := range r.synced {
+			songs += result.songs
+		}
+		fmt.Printf("  ✅ Checked: %d playlists (%d songs)\n", len(r.synced), songs)
+	} else {
+		added, notFound := 0, 0
+		for _, result := range r.synced {
+			added += result.added
+			notFound += result.notFound
+		}
+		fmt.Printf("  ✅ Synced: %d playlists (%d songs added, %d not found)\n", len(r.synced), added, notFound)
+	}
+	if len(r.failures) > 0 {
+		fmt.Printf("  ❌ Failed: %d playlists\n", len(r.failures))
+		for _, failure := range r.failures {
+			fmt.Printf("    - %s: %v\n", failure.source, failure.err)
+		}
+	}
+}
+
+// syncEntry builds or updates the playlist of a manifest entry. In a dry run
+// it only loads the entry's file, so it must not be called for prompts.
+func syncEntry(ctx context.Context, openaiService *openai.Service, spotifyService *spotify.Service, entry manifest.Entry, dryRun bool, logger *slog.Logger) (syncResult, error) {
+	// A playlist URI or URL picks the playlist to update instead of naming it
+	playlistName, target := entry.Name, ""
+	if spotify.IsLink(playlistName) {
+		if entry.Create {
+			return syncResult{}, fmt.Errorf("create needs a playlist name, not a playlist URI")
+		}
+		target, playlistName = playlistName, ""
+	}
+
+	var (
+		playlistResp *openai.PlaylistResponse
+		fromFile     *playlistfile.Playlist
+		source       string
+		err          error
+	)
+	if entry.File != "" {
+		playlistResp, fromFile, err = loadPlaylistFile(entry.File, playlistfile.Options{Format: entry.Format, Columns: entry.Columns}, playlistName, logger)
+		if err != nil {
+			return syncResult{}, fmt.Errorf("failed to load playlist from file: %w", err)
+		}
+		source = fileSource(entry.File, fromFile)
+	} else {
+		if openaiService == nil {
+			return syncResult{}, fmt.Errorf("OpenAI API key is required for AI playlist generation")
+		}
+		songCount := entry.Songs
+		if songCount == 0 {
+			songCount = defaultSongCount
+		}
+		playlistResp, err = openaiService.GeneratePlaylistWithOptions(ctx, entry.Prompts, openai.GenerateOptions{
+			SongCount: songCount,
+			Clean:     spotifyService.Clean(),
+		})
+		if err != nil {
+			return syncResult{}, fmt.Errorf("failed to generate playlist: %w", err)
+		}
+		if playlistName != "" {
+			playlistResp.PlaylistName = playlistName
+		}
+		source = registry.PromptSource(entry.Prompts)
+	}
+	if entry.Description != "" {
+		playlistResp.Description = entry.Description
+	}
+
+	result := syncResult{name: playlistResp.PlaylistName, songs: len(playlistResp.Songs)}
+	if dryRun {
+		return result, nil
+	}
+
+	visibility, _ := spotify.ParseVisibility(entry.Visibility)
+	applyVisibility(spotifyService,
+		visibility == spotify.VisibilityPublic,
+		visibility == spotify.VisibilityPrivate,
+		visibility == spotify.VisibilityCollaborative,
+		fromFile)
+
+	playlist, searchResults, err := spotifyService.CreateOrUpdatePlaylist(ctx, playlistResp, spotify.PlaylistOptions{
+		ForceCreate: entry.Create,
+		Target:      target,
+		Rename:      playlistName != "",
+		Append:      entry.Append,
+		MaxTracks:   entry.MaxTracks,
+		Source:      source,
+	})
+	if err != nil {
+		return syncResult{}, fmt.Errorf("failed to create/update Spotify playlist: %w", err)
+	}
+
+	result.name = playlist.Name
+	result.url = playlist.ExternalURLs["spotify"]
+	for _, searchResult := range searchResults {
+		switch {
+		case searchResult.Added():
+			result.added++
+		case !searchResult.Found && !searchResult.Skipped && !searchResult.Explicit && !searchResult.Excluded && !searchResult.Duplicate && !searchResult.Trimmed:
+			result.notFound++
+		}
+	}
+	return result, nil
+}
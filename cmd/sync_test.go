@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"auto-spotify/internal/spotify"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncCmd_DryRun(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "metal-songs.txt"), []byte("Metallica - One\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "road-trip.txt"), []byte("Toto - Africa\n"), 0644))
+	spotifyService := spotify.NewService("test-id", "test-secret", "http://localhost:8080/callback")
+
+	syncCmd := NewSyncCmd(nil, spotifyService)
+	syncCmd.SetArgs([]string{dir, "--dry-run"})
+
+	require.NoError(t, syncCmd.Execute())
+}
+
+func TestSyncCmd_ContinuesPastFailures(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "mix.txt"), []byte("Toto - Africa\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "empty.txt"), []byte("# nothing yet\n"), 0644))
+	manifestPath := filepath.Join(dir, "playlists.yaml")
+	require.NoError(t, os.WriteFile(manifestPath, []byte(`
+playlists:
+  - file: empty.txt
+  - file: missing.txt
+  - file: mix.txt
+    name: Mix
+  - prompts: [road trip]
+`), 0644))
+	spotifyService := spotify.NewService("test-id", "test-secret", "http://localhost:8080/callback")
+
+	syncCmd := NewSyncCmd(nil, spotifyService)
+	syncCmd.SetArgs([]string{manifestPath, "--dry-run"})
+
+	err := syncCmd.Execute()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "2 of 4 playlists failed to sync")
+}
+
+func TestSyncCmd_RestoresClean(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "mix.txt"), []byte("Toto - Africa\n"), 0644))
+	manifestPath := filepath.Join(dir, "playlists.yaml")
+	require.NoError(t, os.WriteFile(manifestPath, []byte("playlists:\n  - file: mix.txt\n    clean: true\n"), 0644))
+	spotifyService := spotify.NewService("test-id", "test-secret", "http://localhost:8080/callback")
+
+	syncCmd := NewSyncCmd(nil, spotifyService)
+	syncCmd.SetArgs([]string{manifestPath, "--dry-run"})
+
+	require.NoError(t, syncCmd.Execute())
+	assert.False(t, spotifyService.Clean())
+}
+
+func TestSyncCmd_InvalidVisibility(t *testing.T) {
+	manifestPath := filepath.Join(t.TempDir(), "playlists.yaml")
+	require.NoError(t, os.WriteFile(manifestPath, []byte("playlists:\n  - prompts: [road trip]\n    visibility: secret\n"), 0644))
+	spotifyService := spotify.NewService("test-id", "test-secret", "http://localhost:8080/callback")
+
+	syncCmd := NewSyncCmd(nil, spotifyService)
+	syncCmd.SetArgs([]string{manifestPath})
+
+	err := syncCmd.Execute()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid playlist visibility")
+}
+
+func TestSyncCmd_RequiresSource(t *testing.T) {
+	spotifyService := spotify.NewService("test-id", "test-secret", "http://localhost:8080/callback")
+
+	syncCmd := NewSyncCmd(nil, spotifyService)
+	syncCmd.SetArgs([]string{})
+
+	assert.Error(t, syncCmd.Execute())
+}
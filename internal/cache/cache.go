@@ -0,0 +1,222 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"auto-spotify/internal/songkey"
+	"auto-spotify/internal/statefile"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// Entry represents a cached search result for a single song
+type Entry struct {
+	Artist   string             `json:"artist"`
+	Title    string             `json:"title"`
+	Market   string             `json:"market,omitempty"`
+	Track    *spotify.FullTrack `json:"track"`
+	CachedAt time.Time          `json:"cached_at"`
+}
+
+// Stats summarizes the contents of the cache
+type Stats struct {
+	Path    string
+	Entries int
+	Fresh   int
+	Expired int
+	Size    int64
+}
+
+// Cache maps normalized artist/title pairs to resolved Spotify tracks.
+// A nil *Cache is valid and behaves as a disabled cache.
+type Cache struct {
+	path    string
+	ttl     time.Duration
+	entries map[string]Entry
+	dirty   bool
+	mu      sync.Mutex
+	now     func() time.Time
+}
+
+// DefaultPath returns the cache file location inside the user cache directory
+func DefaultPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user cache directory: %w", err)
+	}
+	return filepath.Join(dir, "auto-spotify", "search-cache.json"), nil
+}
+
+// Open loads the cache stored at path, starting empty if the file doesn't exist yet
+func Open(path string, ttl time.Duration) (*Cache, error) {
+	c := &Cache{
+		path:    path,
+		ttl:     ttl,
+		entries: make(map[string]Entry),
+		now:     time.Now,
+	}
+
+	if err := statefile.Load(path, "cache", &c.entries); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// entryKey scopes a lookup key to a market, since availability differs per region
+func entryKey(market, artist, title string) string {
+	if market == "" {
+		return songkey.Exact(artist, title)
+	}
+	return strings.ToUpper(market) + ":" + songkey.Exact(artist, title)
+}
+
+// Get returns the cached track for the song in the given market if present and not expired
+func (c *Cache) Get(market, artist, title string) (*spotify.FullTrack, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[entryKey(market, artist, title)]
+	if !ok || entry.Track == nil || c.expired(entry) {
+		return nil, false
+	}
+	return entry.Track, true
+}
+
+// Put stores the resolved track for the song in the given market
+func (c *Cache) Put(market, artist, title string, track *spotify.FullTrack) {
+	if c == nil || track == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[entryKey(market, artist, title)] = Entry{
+		Artist:   artist,
+		Title:    title,
+		Market:   market,
+		Track:    track,
+		CachedAt: c.now(),
+	}
+	c.dirty = true
+}
+
+// Save writes the cache to disk if it has changed since it was loaded
+func (c *Cache) Save() error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+	if err := statefile.Write(c.path, "cache", data); err != nil {
+		return err
+	}
+
+	c.dirty = false
+	return nil
+}
+
+// Clear removes every entry from the cache and returns how many were removed
+func (c *Cache) Clear() int {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := len(c.entries)
+	c.entries = make(map[string]Entry)
+	c.dirty = true
+	return removed
+}
+
+// Prune removes expired entries and returns how many were removed
+func (c *Cache) Prune() int {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for key, entry := range c.entries {
+		if c.expired(entry) {
+			delete(c.entries, key)
+			removed++
+		}
+	}
+	if removed > 0 {
+		c.dirty = true
+	}
+	return removed
+}
+
+// Stats reports the number of fresh and expired entries and the file size
+func (c *Cache) Stats() Stats {
+	if c == nil {
+		return Stats{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := Stats{
+		Path:    c.path,
+		Entries: len(c.entries),
+	}
+	for _, entry := range c.entries {
+		if c.expired(entry) {
+			stats.Expired++
+		} else {
+			stats.Fresh++
+		}
+	}
+	if info, err := os.Stat(c.path); err == nil {
+		stats.Size = info.Size()
+	}
+
+	return stats
+}
+
+// Path returns the location of the cache file
+func (c *Cache) Path() string {
+	if c == nil {
+		return ""
+	}
+	return c.path
+}
+
+// TTL returns how long entries stay valid
+func (c *Cache) TTL() time.Duration {
+	if c == nil {
+		return 0
+	}
+	return c.ttl
+}
+
+func (c *Cache) expired(entry Entry) bool {
+	return c.ttl > 0 && c.now().Sub(entry.CachedAt) > c.ttl
+}
@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmb3/spotify/v2"
+)
+
+func TestOpen_MissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "cache.json")
+
+	c, err := Open(path, time.Hour)
+
+	require.NoError(t, err)
+	assert.Equal(t, path, c.Path())
+	assert.Equal(t, 0, c.Stats().Entries)
+}
+
+func TestOpen_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0644))
+
+	c, err := Open(path, time.Hour)
+
+	assert.Nil(t, c)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse cache file")
+}
+
+func TestCache_PutGetSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "auto-spotify", "cache.json")
+	c, err := Open(path, time.Hour)
+	require.NoError(t, err)
+
+	track := testTrack("abc123", "Master of Puppets")
+	c.Put("", "Metallica", "Master of Puppets", track)
+
+	cached, ok := c.Get("", "metallica", "master of puppets")
+	require.True(t, ok)
+	assert.Equal(t, spotify.ID("abc123"), cached.ID)
+
+	require.NoError(t, c.Save())
+
+	// Reload from disk
+	reloaded, err := Open(path, time.Hour)
+	require.NoError(t, err)
+
+	cached, ok = reloaded.Get("", "Metallica", "Master of Puppets")
+	require.True(t, ok)
+	assert.Equal(t, "Master of Puppets", cached.Name)
+}
+
+func TestCache_MarketScoped(t *testing.T) {
+	c, err := Open(filepath.Join(t.TempDir(), "cache.json"), time.Hour)
+	require.NoError(t, err)
+
+	c.Put("US", "Metallica", "One", testTrack("us-id", "One"))
+	c.Put("DE", "Metallica", "One", testTrack("de-id", "One"))
+
+	cached, ok := c.Get("us", "Metallica", "One")
+	require.True(t, ok)
+	assert.Equal(t, spotify.ID("us-id"), cached.ID)
+
+	cached, ok = c.Get("DE", "Metallica", "One")
+	require.True(t, ok)
+	assert.Equal(t, spotify.ID("de-id"), cached.ID)
+
+	_, ok = c.Get("GB", "Metallica", "One")
+	assert.False(t, ok)
+}
+
+func TestCache_Expiration(t *testing.T) {
+	c, err := Open(filepath.Join(t.TempDir(), "cache.json"), time.Hour)
+	require.NoError(t, err)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	c.Put("", "Metallica", "One", testTrack("1", "One"))
+	c.Put("", "Metallica", "Fade to Black", testTrack("2", "Fade to Black"))
+
+	now = now.Add(30 * time.Minute)
+	_, ok := c.Get("", "Metallica", "One")
+	assert.True(t, ok)
+
+	now = now.Add(time.Hour)
+	_, ok = c.Get("", "Metallica", "One")
+	assert.False(t, ok)
+
+	stats := c.Stats()
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, 2, stats.Expired)
+	assert.Equal(t, 0, stats.Fresh)
+
+	assert.Equal(t, 2, c.Prune())
+	assert.Equal(t, 0, c.Stats().Entries)
+}
+
+func TestCache_Clear(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	c, err := Open(path, time.Hour)
+	require.NoError(t, err)
+
+	c.Put("", "Metallica", "One", testTrack("1", "One"))
+	require.NoError(t, c.Save())
+
+	assert.Equal(t, 1, c.Clear())
+	require.NoError(t, c.Save())
+
+	reloaded, err := Open(path, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 0, reloaded.Stats().Entries)
+}
+
+func TestCache_NilIsDisabled(t *testing.T) {
+	var c *Cache
+
+	c.Put("", "Metallica", "One", testTrack("1", "One"))
+	_, ok := c.Get("", "Metallica", "One")
+
+	assert.False(t, ok)
+	assert.NoError(t, c.Save())
+	assert.Equal(t, 0, c.Clear())
+	assert.Equal(t, Stats{}, c.Stats())
+}
+
+func testTrack(id, name string) *spotify.FullTrack {
+	return &spotify.FullTrack{
+		SimpleTrack: spotify.SimpleTrack{
+			ID:   spotify.ID(id),
+			Name: name,
+		},
+	}
+}
@@ -3,14 +3,22 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
+
+	"auto-spotify/internal/visibility"
 
 	"github.com/joho/godotenv"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	OpenAI  OpenAIConfig
-	Spotify SpotifyConfig
+	OpenAI    OpenAIConfig
+	Spotify   SpotifyConfig
+	Cache     CacheConfig
+	Overrides OverridesConfig
+	Registry  RegistryConfig
+	History   HistoryConfig
+	Library   LibraryConfig
 }
 
 // OpenAIConfig holds OpenAI API configuration
@@ -23,6 +31,34 @@ type SpotifyConfig struct {
 	ClientID     string
 	ClientSecret string
 	RedirectURL  string
+	Market       string                // Empty means the current user's country
+	Visibility   visibility.Visibility // Empty keeps existing playlists as they are
+}
+
+// CacheConfig holds search result cache configuration
+type CacheConfig struct {
+	Path string        // Empty means the default location in the user cache directory
+	TTL  time.Duration // Zero disables the cache
+}
+
+// OverridesConfig holds manual match overrides configuration
+type OverridesConfig struct {
+	Path string // Empty means the default location in the user config directory
+}
+
+// RegistryConfig holds the registry of generated playlists configuration
+type RegistryConfig struct {
+	Path string // Empty means the default location in the user config directory
+}
+
+// HistoryConfig holds the playlist version history configuration
+type HistoryConfig struct {
+	Path string // Empty means the default location in the user config directory
+}
+
+// LibraryConfig holds the local music library configuration
+type LibraryConfig struct {
+	Root string // Folder with music files that exported playlists can point to; empty disables it
 }
 
 // Load loads configuration from environment variables and .env file
@@ -38,9 +74,37 @@ func Load() (*Config, error) {
 			ClientID:     os.Getenv("SPOTIFY_CLIENT_ID"),
 			ClientSecret: os.Getenv("SPOTIFY_CLIENT_SECRET"),
 			RedirectURL:  getEnvOrDefault("SPOTIFY_REDIRECT_URL", "http://127.0.0.1:8080/callback"),
+			Market:       os.Getenv("SPOTIFY_MARKET"),
 		},
 	}
 
+	playlistVisibility, err := visibility.Parse(os.Getenv("SPOTIFY_PLAYLIST_VISIBILITY"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SPOTIFY_PLAYLIST_VISIBILITY: %w", err)
+	}
+	cfg.Spotify.Visibility = playlistVisibility
+
+	cacheTTL, err := time.ParseDuration(getEnvOrDefault("SPOTIFY_CACHE_TTL", "720h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SPOTIFY_CACHE_TTL: %w", err)
+	}
+	cfg.Cache = CacheConfig{
+		Path: os.Getenv("SPOTIFY_CACHE_PATH"),
+		TTL:  cacheTTL,
+	}
+	cfg.Overrides = OverridesConfig{
+		Path: os.Getenv("SPOTIFY_OVERRIDES_PATH"),
+	}
+	cfg.Registry = RegistryConfig{
+		Path: os.Getenv("SPOTIFY_REGISTRY_PATH"),
+	}
+	cfg.History = HistoryConfig{
+		Path: os.Getenv("SPOTIFY_HISTORY_PATH"),
+	}
+	cfg.Library = LibraryConfig{
+		Root: os.Getenv("MUSIC_LIBRARY_ROOT"),
+	}
+
 	// Validate required configuration
 	// Note: OPENAI_API_KEY is optional for file-based playlists
 	if cfg.Spotify.ClientID == "" {
@@ -3,6 +3,9 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
+
+	"auto-spotify/internal/visibility"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -139,6 +142,86 @@ func TestLoad_MissingSpotifyClientSecret(t *testing.T) {
 	assert.EqualError(t, err, "SPOTIFY_CLIENT_SECRET is required")
 }
 
+func TestLoad_CacheSettings(t *testing.T) {
+	oldSpotifyID := os.Getenv("SPOTIFY_CLIENT_ID")
+	oldSpotifySecret := os.Getenv("SPOTIFY_CLIENT_SECRET")
+	oldCacheTTL := os.Getenv("SPOTIFY_CACHE_TTL")
+	oldCachePath := os.Getenv("SPOTIFY_CACHE_PATH")
+
+	defer func() {
+		setOrUnset("SPOTIFY_CLIENT_ID", oldSpotifyID)
+		setOrUnset("SPOTIFY_CLIENT_SECRET", oldSpotifySecret)
+		setOrUnset("SPOTIFY_CACHE_TTL", oldCacheTTL)
+		setOrUnset("SPOTIFY_CACHE_PATH", oldCachePath)
+	}()
+
+	os.Setenv("SPOTIFY_CLIENT_ID", "test-spotify-id")
+	os.Setenv("SPOTIFY_CLIENT_SECRET", "test-spotify-secret")
+
+	// Defaults
+	os.Unsetenv("SPOTIFY_CACHE_TTL")
+	os.Unsetenv("SPOTIFY_CACHE_PATH")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 720*time.Hour, cfg.Cache.TTL)
+	assert.Equal(t, "", cfg.Cache.Path)
+
+	// Custom values
+	os.Setenv("SPOTIFY_CACHE_TTL", "24h")
+	os.Setenv("SPOTIFY_CACHE_PATH", "/tmp/auto-spotify-cache.json")
+
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, cfg.Cache.TTL)
+	assert.Equal(t, "/tmp/auto-spotify-cache.json", cfg.Cache.Path)
+
+	// Invalid TTL
+	os.Setenv("SPOTIFY_CACHE_TTL", "forever")
+
+	cfg, err = Load()
+	assert.Nil(t, cfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid SPOTIFY_CACHE_TTL")
+}
+
+func TestLoad_PlaylistVisibility(t *testing.T) {
+	oldSpotifyID := os.Getenv("SPOTIFY_CLIENT_ID")
+	oldSpotifySecret := os.Getenv("SPOTIFY_CLIENT_SECRET")
+	oldVisibility := os.Getenv("SPOTIFY_PLAYLIST_VISIBILITY")
+
+	defer func() {
+		setOrUnset("SPOTIFY_CLIENT_ID", oldSpotifyID)
+		setOrUnset("SPOTIFY_CLIENT_SECRET", oldSpotifySecret)
+		setOrUnset("SPOTIFY_PLAYLIST_VISIBILITY", oldVisibility)
+	}()
+
+	os.Setenv("SPOTIFY_CLIENT_ID", "test-spotify-id")
+	os.Setenv("SPOTIFY_CLIENT_SECRET", "test-spotify-secret")
+
+	// Default
+	os.Unsetenv("SPOTIFY_PLAYLIST_VISIBILITY")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Spotify.Visibility)
+
+	// Custom value
+	os.Setenv("SPOTIFY_PLAYLIST_VISIBILITY", " Public ")
+
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, visibility.Public, cfg.Spotify.Visibility)
+
+	// Invalid value
+	os.Setenv("SPOTIFY_PLAYLIST_VISIBILITY", "secret")
+
+	cfg, err = Load()
+	assert.Nil(t, cfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid SPOTIFY_PLAYLIST_VISIBILITY")
+}
+
 func TestGetEnvOrDefault(t *testing.T) {
 	tests := []struct {
 		name         string
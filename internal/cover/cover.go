@@ -0,0 +1,254 @@
+package cover
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math"
+	"os"
+	"strings"
+)
+
+// Size is the width and height of generated covers in pixels
+const Size = 640
+
+// MaxBytes is the largest JPEG Spotify accepts as a playlist cover. Uploads
+// are base64 encoded and limited to 256 KB, which leaves 192 KB of JPEG data.
+const MaxBytes = 256 * 1024 / 4 * 3
+
+const (
+	margin       = 64 // Space kept free around the title
+	maxLineChars = 10 // Longest title line before wrapping
+	maxLines     = 5  // Lines shown before the title is cut off
+	maxScale     = 20 // Largest font pixel size
+)
+
+// Load reads a JPEG cover from disk, re-encoding it at a lower quality if
+// it's too large for Spotify
+func Load(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cover image: %w", err)
+	}
+
+	_, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || format != "jpeg" {
+		return nil, fmt.Errorf("cover image %s must be a JPEG", path)
+	}
+
+	if len(data) <= MaxBytes {
+		return data, nil
+	}
+
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode cover image: %w", err)
+	}
+	return encode(img)
+}
+
+// Generate renders a typographic cover showing the playlist name on a
+// gradient whose colors are derived from the name
+func Generate(name string) ([]byte, error) {
+	from, to, accent := palette(name)
+	img := image.NewRGBA(image.Rect(0, 0, Size, Size))
+
+	// Diagonal gradient with a soft circle in the accent color
+	cx, cy, radius := float64(Size)*0.75, float64(Size)*0.8, float64(Size)*0.45
+	for y := 0; y < Size; y++ {
+		for x := 0; x < Size; x++ {
+			c := mix(from, to, float64(x+y)/float64(2*(Size-1)))
+			if math.Hypot(float64(x)-cx, float64(y)-cy) < radius {
+				c = mix(c, accent, 0.35)
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+
+	drawTitle(img, wrap(name))
+
+	return encode(img)
+}
+
+// encode writes img as a JPEG, lowering the quality and finally the size
+// until it fits in MaxBytes
+func encode(img image.Image) ([]byte, error) {
+	for img.Bounds().Dx() >= 64 {
+		for quality := 90; quality >= 40; quality -= 10 {
+			var buf bytes.Buffer
+			if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+				return nil, fmt.Errorf("failed to encode cover image: %w", err)
+			}
+			if buf.Len() <= MaxBytes {
+				return buf.Bytes(), nil
+			}
+		}
+		img = halve(img)
+	}
+
+	return nil, fmt.Errorf("cover image is too large for Spotify (max %d KB)", MaxBytes/1024)
+}
+
+// halve scales img down to half its size by averaging 2x2 blocks
+func halve(img image.Image) image.Image {
+	bounds := img.Bounds()
+	small := image.NewRGBA(image.Rect(0, 0, bounds.Dx()/2, bounds.Dy()/2))
+
+	for y := 0; y < small.Bounds().Dy(); y++ {
+		for x := 0; x < small.Bounds().Dx(); x++ {
+			var r, g, b uint32
+			for _, p := range [4]image.Point{{0, 0}, {1, 0}, {0, 1}, {1, 1}} {
+				pr, pg, pb, _ := img.At(bounds.Min.X+2*x+p.X, bounds.Min.Y+2*y+p.Y).RGBA()
+				r, g, b = r+pr, g+pg, b+pb
+			}
+			small.SetRGBA(x, y, color.RGBA{uint8(r / 4 >> 8), uint8(g / 4 >> 8), uint8(b / 4 >> 8), 255})
+		}
+	}
+
+	return small
+}
+
+// wrap splits the title into upper-case lines of at most maxLineChars
+// characters, dropping characters the font can't draw
+func wrap(title string) []string {
+	var words []string
+	for _, word := range strings.Fields(strings.ToUpper(title)) {
+		word = strings.Map(func(r rune) rune {
+			if _, ok := glyphs[r]; ok {
+				return r
+			}
+			return -1
+		}, word)
+
+		// Break up words that don't fit on a line
+		for len(word) > maxLineChars {
+			words = append(words, word[:maxLineChars])
+			word = word[maxLineChars:]
+		}
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+
+	var lines []string
+	for _, word := range words {
+		last := len(lines) - 1
+		if last >= 0 && len(lines[last])+1+len(word) <= maxLineChars {
+			lines[last] += " " + word
+		} else {
+			lines = append(lines, word)
+		}
+	}
+
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		// Make room for an ellipsis, dropping whole words where possible
+		last := lines[maxLines-1]
+		for len(last) > maxLineChars-3 {
+			if i := strings.LastIndex(last, " "); i > 0 {
+				last = last[:i]
+			} else {
+				last = last[:maxLineChars-3]
+			}
+		}
+		lines[maxLines-1] = last + "..."
+	}
+
+	return lines
+}
+
+// drawTitle draws the lines centered on the image with a drop shadow
+func drawTitle(img *image.RGBA, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+
+	longest := 0
+	for _, line := range lines {
+		if len(line) > longest {
+			longest = len(line)
+		}
+	}
+
+	// Glyphs are separated by one font pixel, lines by two
+	space := Size - 2*margin
+	scale := min(maxScale, space/(longest*(glyphWidth+1)-1), space/(len(lines)*(glyphHeight+2)-2))
+	lineHeight := (glyphHeight + 2) * scale
+	top := (Size - (len(lines)*lineHeight - 2*scale)) / 2
+	shadow := max(1, scale/3)
+
+	for i, line := range lines {
+		left := (Size - (len(line)*(glyphWidth+1)-1)*scale) / 2
+		y := top + i*lineHeight
+		drawText(img, line, left+shadow, y+shadow, scale, color.RGBA{0, 0, 0, 255})
+		drawText(img, line, left, y, scale, color.RGBA{255, 255, 255, 255})
+	}
+}
+
+// drawText draws a single line with its top-left corner at x, y
+func drawText(img *image.RGBA, text string, x, y, scale int, c color.RGBA) {
+	for i, r := range []rune(text) {
+		glyph := glyphs[r]
+		for row, bits := range glyph {
+			for col, bit := range bits {
+				if bit != '#' {
+					continue
+				}
+				px := x + (i*(glyphWidth+1)+col)*scale
+				py := y + row*scale
+				for dy := 0; dy < scale; dy++ {
+					for dx := 0; dx < scale; dx++ {
+						img.SetRGBA(px+dx, py+dy, c)
+					}
+				}
+			}
+		}
+	}
+}
+
+// palette derives gradient and accent colors from the playlist name, so the
+// same playlist always gets the same cover
+func palette(name string) (from, to, accent color.RGBA) {
+	h := fnv.New32a()
+	h.Write([]byte(strings.ToLower(strings.TrimSpace(name))))
+	hue := float64(h.Sum32() % 360)
+
+	return hsl(hue, 0.55, 0.28), hsl(hue+50, 0.65, 0.5), hsl(hue+180, 0.7, 0.6)
+}
+
+// hsl converts a hue in degrees, saturation and lightness to RGB
+func hsl(hue, saturation, lightness float64) color.RGBA {
+	hue = math.Mod(hue, 360) / 60
+	chroma := (1 - math.Abs(2*lightness-1)) * saturation
+	x := chroma * (1 - math.Abs(math.Mod(hue, 2)-1))
+
+	var r, g, b float64
+	switch {
+	case hue < 1:
+		r, g = chroma, x
+	case hue < 2:
+		r, g = x, chroma
+	case hue < 3:
+		g, b = chroma, x
+	case hue < 4:
+		g, b = x, chroma
+	case hue < 5:
+		r, b = x, chroma
+	default:
+		r, b = chroma, x
+	}
+
+	m := lightness - chroma/2
+	return color.RGBA{uint8((r + m) * 255), uint8((g + m) * 255), uint8((b + m) * 255), 255}
+}
+
+// mix blends a towards b by t (0 to 1)
+func mix(a, b color.RGBA, t float64) color.RGBA {
+	blend := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*t)
+	}
+	return color.RGBA{blend(a.R, b.R), blend(a.G, b.G), blend(a.B, b.B), 255}
+}
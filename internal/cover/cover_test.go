@@ -0,0 +1,124 @@
+package cover
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	data, err := Generate("Road Trip Classics")
+
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(data), MaxBytes)
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg", format)
+	assert.Equal(t, Size, config.Width)
+	assert.Equal(t, Size, config.Height)
+
+	// The same name always renders the same cover
+	again, err := Generate("Road Trip Classics")
+	require.NoError(t, err)
+	assert.Equal(t, data, again)
+
+	// Names without drawable characters still get a cover
+	_, err = Generate("🎵🎶")
+	assert.NoError(t, err)
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		title    string
+		expected []string
+	}{
+		{title: "Road Trip Classics", expected: []string{"ROAD TRIP", "CLASSICS"}},
+		{title: "Chill Vibes", expected: []string{"CHILL", "VIBES"}},
+		{title: "Rock 'n' Roll", expected: []string{"ROCK 'N'", "ROLL"}},
+		{title: "Supercalifragilistic", expected: []string{"SUPERCALIF", "RAGILISTIC"}},
+		{title: "Café Ñ", expected: []string{"CAF"}},
+		{title: "", expected: nil},
+		{
+			title:    "one two three four five six seven eight nine ten eleven twelve",
+			expected: []string{"ONE TWO", "THREE FOUR", "FIVE SIX", "SEVEN", "EIGHT..."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			assert.Equal(t, tt.expected, wrap(tt.title))
+		})
+	}
+}
+
+func TestPalette_DiffersByName(t *testing.T) {
+	fromA, _, _ := palette("Road Trip")
+	fromB, _, _ := palette("Office Party")
+	fromC, _, _ := palette("  road trip ")
+
+	assert.NotEqual(t, fromA, fromB)
+	assert.Equal(t, fromA, fromC)
+}
+
+func TestHSL(t *testing.T) {
+	assert.Equal(t, color.RGBA{255, 0, 0, 255}, hsl(0, 1, 0.5))
+	assert.Equal(t, color.RGBA{0, 255, 0, 255}, hsl(120, 1, 0.5))
+	assert.Equal(t, color.RGBA{0, 0, 255, 255}, hsl(240, 1, 0.5))
+}
+
+func TestLoad_SmallJPEG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 300, 300)), nil))
+	path := filepath.Join(t.TempDir(), "cover.jpg")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+
+	data, err := Load(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, buf.Bytes(), data)
+}
+
+func TestLoad_LargeJPEGIsShrunk(t *testing.T) {
+	// Random noise compresses badly, so this is well over the limit
+	img := image.NewRGBA(image.Rect(0, 0, 1200, 1200))
+	rng := rand.New(rand.NewSource(1))
+	rng.Read(img.Pix)
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100}))
+	require.Greater(t, buf.Len(), MaxBytes)
+
+	path := filepath.Join(t.TempDir(), "cover.jpg")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+
+	data, err := Load(path)
+
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(data), MaxBytes)
+	_, format, err := image.DecodeConfig(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg", format)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing.jpg"))
+	assert.Error(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 10, 10))))
+	path := filepath.Join(t.TempDir(), "cover.png")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+
+	_, err = Load(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be a JPEG")
+}
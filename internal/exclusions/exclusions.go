@@ -0,0 +1,83 @@
+package exclusions
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// List holds artists, tracks and playlists that must be kept out of generated
+// playlists. Tracks are Spotify track URIs, URLs or IDs; playlists are names
+// of playlists whose songs count as "already heard".
+//
+// File format, one entry per line:
+//
+//	artist: Nickelback
+//	track: spotify:track:4uLU6hMCjMI75M1A2tKUQC
+//	playlist: Heard It All
+type List struct {
+	Artists   []string
+	Tracks    []string
+	Playlists []string
+}
+
+// Load reads an exclusion list file
+func Load(path string) (*List, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open exclusion file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	list := &List{}
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+
+		// Skip empty lines and comments
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		kind, value, ok := strings.Cut(line, ":")
+		value = strings.TrimSpace(value)
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid exclusion in %s (line %d): expected 'artist:', 'track:' or 'playlist:'", path, lineNum)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(kind)) {
+		case "artist":
+			list.Artists = append(list.Artists, value)
+		case "track":
+			list.Tracks = append(list.Tracks, value)
+		case "playlist":
+			list.Playlists = append(list.Playlists, value)
+		default:
+			return nil, fmt.Errorf("invalid exclusion in %s (line %d): unknown kind %q", path, lineNum, kind)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading exclusion file %s: %w", path, err)
+	}
+
+	return list, nil
+}
+
+// Merge adds every entry of other to the list
+func (l *List) Merge(other *List) {
+	if other == nil {
+		return
+	}
+	l.Artists = append(l.Artists, other.Artists...)
+	l.Tracks = append(l.Tracks, other.Tracks...)
+	l.Playlists = append(l.Playlists, other.Playlists...)
+}
+
+// Empty reports whether the list has no entries
+func (l *List) Empty() bool {
+	return l == nil || len(l.Artists)+len(l.Tracks)+len(l.Playlists) == 0
+}
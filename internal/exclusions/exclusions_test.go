@@ -0,0 +1,75 @@
+package exclusions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Success(t *testing.T) {
+	content := `# Office bans
+artist: Nickelback
+Artist:  Creed
+track: spotify:track:4uLU6hMCjMI75M1A2tKUQC
+
+// Songs we've heard enough
+playlist: Heard It All`
+
+	path := filepath.Join(t.TempDir(), "exclude.txt")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	list, err := Load(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Nickelback", "Creed"}, list.Artists)
+	assert.Equal(t, []string{"spotify:track:4uLU6hMCjMI75M1A2tKUQC"}, list.Tracks)
+	assert.Equal(t, []string{"Heard It All"}, list.Playlists)
+	assert.False(t, list.Empty())
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		errorMsg string
+	}{
+		{name: "missing kind", content: "Nickelback", errorMsg: "expected 'artist:'"},
+		{name: "missing value", content: "artist:", errorMsg: "expected 'artist:'"},
+		{name: "unknown kind", content: "album: Silver Side Up", errorMsg: "unknown kind"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "exclude.txt")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+
+			list, err := Load(path)
+
+			assert.Nil(t, list)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "line 1")
+			assert.Contains(t, err.Error(), tt.errorMsg)
+		})
+	}
+
+	_, err := Load(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
+
+func TestList_Merge(t *testing.T) {
+	list := &List{Artists: []string{"Nickelback"}}
+	assert.False(t, list.Empty())
+
+	list.Merge(&List{Artists: []string{"Creed"}, Playlists: []string{"Heard It All"}})
+	list.Merge(nil)
+
+	assert.Equal(t, []string{"Nickelback", "Creed"}, list.Artists)
+	assert.Equal(t, []string{"Heard It All"}, list.Playlists)
+
+	var empty *List
+	assert.True(t, empty.Empty())
+	assert.True(t, (&List{}).Empty())
+}
@@ -0,0 +1,159 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"auto-spotify/internal/statefile"
+)
+
+// MaxVersions is how many versions are kept per playlist; older ones are dropped
+const MaxVersions = 20
+
+// Track is a track saved in a playlist version
+type Track struct {
+	ID     string `json:"id"`
+	Artist string `json:"artist"`
+	Title  string `json:"title"`
+}
+
+// Version is a playlist's track list as it was before a change
+type Version struct {
+	Number     int       `json:"number"`
+	Action     string    `json:"action"` // The change that followed, e.g. "update", "append", "dedupe" or "undo"
+	Name       string    `json:"name"`
+	SnapshotID string    `json:"snapshot_id"`
+	Tracks     []Track   `json:"tracks"`
+	SavedAt    time.Time `json:"saved_at"`
+}
+
+// History keeps earlier versions of playlists so changes can be undone.
+// A nil *History is valid and records nothing.
+type History struct {
+	path      string
+	playlists map[string][]Version // By playlist ID, oldest first
+	dirty     bool
+	mu        sync.Mutex
+	now       func() time.Time
+}
+
+// DefaultPath returns the history file location inside the user config directory
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user config directory: %w", err)
+	}
+	return filepath.Join(dir, "auto-spotify", "history.json"), nil
+}
+
+// Open loads the history stored at path, starting empty if the file doesn't exist yet
+func Open(path string) (*History, error) {
+	h := &History{
+		path:      path,
+		playlists: make(map[string][]Version),
+		now:       time.Now,
+	}
+
+	if err := statefile.Load(path, "history", &h.playlists); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// Add records a version of the playlist, numbering it after the previous one
+func (h *History) Add(playlistID string, version Version) Version {
+	if h == nil {
+		return version
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	versions := h.playlists[playlistID]
+	version.Number = 1
+	if len(versions) > 0 {
+		version.Number = versions[len(versions)-1].Number + 1
+	}
+	version.SavedAt = h.now()
+
+	versions = append(versions, version)
+	if len(versions) > MaxVersions {
+		versions = versions[len(versions)-MaxVersions:]
+	}
+	h.playlists[playlistID] = versions
+	h.dirty = true
+
+	return version
+}
+
+// Versions returns the saved versions of the playlist, newest first
+func (h *History) Versions(playlistID string) []Version {
+	if h == nil {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	versions := h.playlists[playlistID]
+	newestFirst := make([]Version, 0, len(versions))
+	for i := len(versions) - 1; i >= 0; i-- {
+		newestFirst = append(newestFirst, versions[i])
+	}
+	return newestFirst
+}
+
+// Version returns the playlist version with the given number
+func (h *History) Version(playlistID string, number int) (Version, bool) {
+	if h == nil {
+		return Version{}, false
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, version := range h.playlists[playlistID] {
+		if version.Number == number {
+			return version, true
+		}
+	}
+	return Version{}, false
+}
+
+// Path returns the location of the history file
+func (h *History) Path() string {
+	if h == nil {
+		return ""
+	}
+	return h.path
+}
+
+// Save writes the history to disk if it has changed since it was loaded
+func (h *History) Save() error {
+	if h == nil {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(h.playlists)
+	if err != nil {
+		return fmt.Errorf("failed to encode history: %w", err)
+	}
+	if err := statefile.Write(h.path, "history", data); err != nil {
+		return err
+	}
+
+	h.dirty = false
+	return nil
+}
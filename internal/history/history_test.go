@@ -0,0 +1,103 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen_MissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "history.json")
+
+	h, err := Open(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, path, h.Path())
+	assert.Empty(t, h.Versions("playlist1"))
+}
+
+func TestOpen_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0644))
+
+	h, err := Open(path)
+
+	assert.Nil(t, h)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse history file")
+}
+
+func TestHistory_AddVersionsSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "auto-spotify", "history.json")
+	h, err := Open(path)
+	require.NoError(t, err)
+
+	savedAt := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	h.now = func() time.Time { return savedAt }
+
+	first := h.Add("playlist1", Version{
+		Action:     "update",
+		Name:       "Metal",
+		SnapshotID: "snap1",
+		Tracks:     []Track{{ID: "track1", Artist: "Metallica", Title: "One"}},
+	})
+	second := h.Add("playlist1", Version{Action: "append", Name: "Metal", SnapshotID: "snap2"})
+	other := h.Add("playlist2", Version{Action: "dedupe"})
+
+	assert.Equal(t, 1, first.Number)
+	assert.Equal(t, 2, second.Number)
+	assert.Equal(t, 1, other.Number)
+	assert.True(t, savedAt.Equal(first.SavedAt))
+
+	require.NoError(t, h.Save())
+
+	reloaded, err := Open(path)
+	require.NoError(t, err)
+
+	versions := reloaded.Versions("playlist1")
+	require.Len(t, versions, 2)
+	assert.Equal(t, 2, versions[0].Number)
+	assert.Equal(t, 1, versions[1].Number)
+
+	version, ok := reloaded.Version("playlist1", 1)
+	require.True(t, ok)
+	assert.Equal(t, "snap1", version.SnapshotID)
+	assert.Equal(t, []Track{{ID: "track1", Artist: "Metallica", Title: "One"}}, version.Tracks)
+
+	_, ok = reloaded.Version("playlist1", 3)
+	assert.False(t, ok)
+}
+
+func TestHistory_KeepsMaxVersions(t *testing.T) {
+	h, err := Open(filepath.Join(t.TempDir(), "history.json"))
+	require.NoError(t, err)
+
+	for i := 0; i < MaxVersions+5; i++ {
+		h.Add("playlist1", Version{SnapshotID: fmt.Sprintf("snap%d", i)})
+	}
+
+	versions := h.Versions("playlist1")
+	require.Len(t, versions, MaxVersions)
+	assert.Equal(t, MaxVersions+5, versions[0].Number)
+	assert.Equal(t, 6, versions[len(versions)-1].Number)
+
+	// Numbers keep counting after old versions are dropped
+	assert.Equal(t, MaxVersions+6, h.Add("playlist1", Version{}).Number)
+}
+
+func TestHistory_Nil(t *testing.T) {
+	var h *History
+
+	h.Add("playlist1", Version{})
+	_, ok := h.Version("playlist1", 1)
+
+	assert.False(t, ok)
+	assert.Nil(t, h.Versions("playlist1"))
+	assert.Equal(t, "", h.Path())
+	assert.NoError(t, h.Save())
+}
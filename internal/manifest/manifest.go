@@ -0,0 +1,199 @@
+// Package manifest reads the list of playlists the sync command builds, from
+// a manifest file or from the playlist files in a directory.
+//
+// Manifest files are YAML. Every playlist is built from a playlist file or
+// from prompts, and the defaults apply to every playlist that doesn't set
+// the option itself:
+//
+//	defaults:
+//	  visibility: private
+//	  max_tracks: 100
+//	playlists:
+//	  - file: metal-songs.txt
+//	    name: My Metal Playlist
+//	  - prompts: ["chill indie rock for studying"]
+//	    songs: 15
+//	    clean: true
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"auto-spotify/internal/playlistfile"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Entry is a playlist to build and the options to build it with
+type Entry struct {
+	File    string   `yaml:"file"`    // Playlist file to load the songs from
+	Prompts []string `yaml:"prompts"` // Prompts to ask the AI for songs, when there's no file
+
+	Name        string            `yaml:"name"`        // Playlist name, or the URI/URL of the playlist to update
+	Description string            `yaml:"description"` // Playlist description
+	Format      string            `yaml:"format"`      // Format of the file, detected from its extension when empty
+	Columns     map[string]string `yaml:"columns"`     // CSV and TSV columns of the song fields
+	Songs       int               `yaml:"songs"`       // Number of songs to ask the AI for (0 for the default)
+	Visibility  string            `yaml:"visibility"`  // "private", "public" or "collaborative"
+	Create      bool              `yaml:"create"`      // Always create a new playlist
+	Append      bool              `yaml:"append"`      // Add the songs to the playlist instead of replacing it
+	MaxTracks   int               `yaml:"max_tracks"`  // Cap on the number of tracks (0 for no cap)
+	Clean       bool              `yaml:"clean"`       // Reject explicit tracks
+
+	Line int `yaml:"-"` // Line of the manifest the entry starts at (0 for directories)
+}
+
+// Source describes where the entry's songs come from, for reports
+func (e Entry) Source() string {
+	if e.File != "" {
+		return e.File
+	}
+	return "prompts: " + strings.Join(e.Prompts, " | ")
+}
+
+// Manifest lists the playlists to build
+type Manifest struct {
+	Path    string // Manifest file or directory the entries were read from
+	Entries []Entry
+}
+
+// document is the YAML layout of a manifest file
+type document struct {
+	Defaults  Entry   `yaml:"defaults"`
+	Playlists []Entry `yaml:"playlists"`
+}
+
+// documentNodes is the same layout kept as nodes, so each playlist can be
+// decoded on top of the defaults
+type documentNodes struct {
+	Defaults  yaml.Node   `yaml:"defaults"`
+	Playlists []yaml.Node `yaml:"playlists"`
+}
+
+// Load reads the manifest file at path. Relative file paths in it are
+// relative to the manifest's directory.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %s: %w", path, err)
+	}
+
+	// Decode strictly once so misspelled options are reported
+	var doc document
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", path, err)
+	}
+	if doc.Defaults.File != "" || len(doc.Defaults.Prompts) > 0 {
+		return nil, fmt.Errorf("invalid manifest %s: defaults can't name a file or prompts", path)
+	}
+	if len(doc.Playlists) == 0 {
+		return nil, fmt.Errorf("no playlists found in manifest %s", path)
+	}
+
+	var nodes documentNodes
+	if err := yaml.Unmarshal(data, &nodes); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", path, err)
+	}
+
+	manifest := &Manifest{Path: path}
+	dir := filepath.Dir(path)
+	for i, node := range nodes.Playlists {
+		var entry Entry
+		if !nodes.Defaults.IsZero() {
+			if err := nodes.Defaults.Decode(&entry); err != nil {
+				return nil, fmt.Errorf("invalid manifest %s: %w", path, err)
+			}
+		}
+		if err := node.Decode(&entry); err != nil {
+			return nil, fmt.Errorf("invalid manifest %s: %w", path, err)
+		}
+		entry.Line = node.Line
+
+		switch {
+		case entry.File != "" && len(entry.Prompts) > 0:
+			return nil, fmt.Errorf("invalid manifest %s: playlist %d (line %d) has both a file and prompts", path, i+1, entry.Line)
+		case entry.File == "" && len(entry.Prompts) == 0:
+			return nil, fmt.Errorf("invalid manifest %s: playlist %d (line %d) needs a file or prompts", path, i+1, entry.Line)
+		case entry.Songs < 0 || entry.MaxTracks < 0:
+			return nil, fmt.Errorf("invalid manifest %s: playlist %d (line %d) has a negative songs or max_tracks", path, i+1, entry.Line)
+		}
+		if entry.File != "" && !filepath.IsAbs(entry.File) {
+			entry.File = filepath.Join(dir, entry.File)
+		}
+
+		manifest.Entries = append(manifest.Entries, entry)
+	}
+
+	return manifest, nil
+}
+
+// FromDir lists every playlist file under dir in lexical order, skipping
+// hidden files and folders and manifests kept next to the playlists, as a
+// manifest without options
+func FromDir(dir string) (*Manifest, error) {
+	manifest := &Manifest{Path: dir}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && playlistfile.IsPlaylistFile(path) && !isManifest(path) {
+			manifest.Entries = append(manifest.Entries, Entry{File: path})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", dir, err)
+	}
+	if len(manifest.Entries) == 0 {
+		return nil, fmt.Errorf("no playlist files found in %s", dir)
+	}
+	return manifest, nil
+}
+
+// isManifest reports whether the YAML file at path is a manifest rather than
+// a playlist, by its top-level playlists or defaults
+func isManifest(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+	default:
+		return false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var top map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &top); err != nil {
+		return false
+	}
+	_, playlists := top["playlists"]
+	_, defaults := top["defaults"]
+	return playlists || defaults
+}
+
+// Open reads the manifest file at path, or lists the playlist files when
+// path is a directory
+func Open(path string) (*Manifest, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return FromDir(path)
+	}
+	return Load(path)
+}
@@ -0,0 +1,144 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	path := writeManifest(t, `
+defaults:
+  visibility: private
+  max_tracks: 100
+  clean: true
+  columns:
+    artist: Band
+playlists:
+  - file: metal-songs.txt
+    name: My Metal Playlist
+  - file: /abs/ranking.csv
+    clean: false
+    columns:
+      title: Song
+  - prompts: ["chill indie rock for studying", "lo-fi"]
+    songs: 15
+    visibility: public
+`)
+
+	m, err := Load(path)
+	require.NoError(t, err)
+	require.Len(t, m.Entries, 3)
+
+	metal := m.Entries[0]
+	assert.Equal(t, filepath.Join(filepath.Dir(path), "metal-songs.txt"), metal.File)
+	assert.Equal(t, "My Metal Playlist", metal.Name)
+	assert.Equal(t, "private", metal.Visibility)
+	assert.Equal(t, 100, metal.MaxTracks)
+	assert.True(t, metal.Clean)
+	assert.Equal(t, 9, metal.Line)
+
+	ranking := m.Entries[1]
+	assert.Equal(t, "/abs/ranking.csv", ranking.File)
+	assert.False(t, ranking.Clean)
+	assert.Equal(t, map[string]string{"artist": "Band", "title": "Song"}, ranking.Columns)
+
+	prompts := m.Entries[2]
+	assert.Equal(t, []string{"chill indie rock for studying", "lo-fi"}, prompts.Prompts)
+	assert.Equal(t, 15, prompts.Songs)
+	assert.Equal(t, "public", prompts.Visibility)
+	assert.Equal(t, "prompts: chill indie rock for studying | lo-fi", prompts.Source())
+
+	// Defaults aren't shared between entries
+	assert.Equal(t, map[string]string{"artist": "Band"}, metal.Columns)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		want    string
+	}{
+		"both":         {"playlists:\n  - file: a.txt\n    prompts: [x]\n", "has both a file and prompts"},
+		"neither":      {"playlists:\n  - name: Mix\n", "playlist 1 (line 2) needs a file or prompts"},
+		"negative":     {"playlists:\n  - file: a.txt\n    max_tracks: -1\n", "negative songs or max_tracks"},
+		"unknown":      {"playlists:\n  - file: a.txt\n    max-tracks: 5\n", "field max-tracks not found"},
+		"empty":        {"defaults:\n  clean: true\n", "no playlists found"},
+		"defaultsFile": {"defaults:\n  file: a.txt\nplaylists:\n  - file: b.txt\n", "defaults can't name a file"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Load(writeManifest(t, tt.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestFromDir(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"metal-songs.txt", "README.md", "road/trip.m3u8", ".git/HEAD.txt", ".hidden.txt"} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("Toto - Africa\n"), 0644))
+	}
+
+	m, err := FromDir(dir)
+	require.NoError(t, err)
+
+	require.Len(t, m.Entries, 2)
+	assert.Equal(t, filepath.Join(dir, "metal-songs.txt"), m.Entries[0].File)
+	assert.Equal(t, filepath.Join(dir, "road", "trip.m3u8"), m.Entries[1].File)
+}
+
+func TestFromDir_SkipsManifest(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"playlists.yaml":  "playlists:\n  - file: metal-songs.txt\n",
+		"defaults.yml":    "defaults:\n  visibility: public\n",
+		"mix.yaml":        "playlist_name: Mix\nsongs:\n  - artist: Toto\n    title: Africa\n",
+		"metal-songs.txt": "Metallica - One\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	m, err := FromDir(dir)
+	require.NoError(t, err)
+
+	require.Len(t, m.Entries, 2)
+	assert.Equal(t, filepath.Join(dir, "metal-songs.txt"), m.Entries[0].File)
+	assert.Equal(t, filepath.Join(dir, "mix.yaml"), m.Entries[1].File)
+}
+
+func TestFromDir_Empty(t *testing.T) {
+	_, err := FromDir(t.TempDir())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no playlist files found")
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "mix.txt"), []byte("Toto - Africa\n"), 0644))
+
+	m, err := Open(dir)
+	require.NoError(t, err)
+	assert.Len(t, m.Entries, 1)
+
+	m, err = Open(writeManifest(t, "playlists:\n  - prompts: [road trip]\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"road trip"}, m.Entries[0].Prompts)
+
+	_, err = Open(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+}
+
+// writeManifest writes a manifest file to a temporary directory
+func writeManifest(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "playlists.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
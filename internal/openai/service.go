@@ -1,12 +1,12 @@
 package openai
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
-	"regexp"
+	"io"
+	"log/slog"
+	"sort"
 	"strings"
 	"time"
 
@@ -16,6 +16,7 @@ import (
 // Service handles OpenAI API interactions
 type Service struct {
 	client *openai.Client
+	logger *slog.Logger
 }
 
 // Song represents a song recommendation
@@ -25,6 +26,19 @@ type Song struct {
 	Album  string `json:"album,omitempty"`
 	Year   int    `json:"year,omitempty"`
 	Reason string `json:"reason,omitempty"`
+	URI    string `json:"uri,omitempty"` // Spotify track URI to use instead of searching
+}
+
+// String formats the song as "Artist - Title", leaving out what isn't known
+func (s Song) String() string {
+	switch {
+	case s.Artist != "" && s.Title != "":
+		return s.Artist + " - " + s.Title
+	case s.Title != "":
+		return s.Title
+	default:
+		return s.URI
+	}
 }
 
 // PlaylistResponse represents the response from OpenAI
@@ -38,30 +52,53 @@ type PlaylistResponse struct {
 func NewService(apiKey string) *Service {
 	return &Service{
 		client: openai.NewClient(apiKey),
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
 	}
 }
 
+// SetLogger sets the logger for diagnostic messages
+func (s *Service) SetLogger(logger *slog.Logger) {
+	s.logger = logger
+}
+
+// GenerateOptions holds optional constraints for playlist generation
+type GenerateOptions struct {
+	SongCount      int
+	Clean          bool          // Avoid songs with explicit lyrics
+	TargetDuration time.Duration // Approximate total runtime to aim for (0 for none)
+	AvoidSongs     []Song        // Songs that must not be suggested again
+	AvoidArtists   []string      // Artists whose songs must not be suggested
+	SeedSongs      []Song        // Existing playlist to suggest more songs like
+}
+
 // GeneratePlaylist generates a playlist based on the given prompt
 func (s *Service) GeneratePlaylist(ctx context.Context, prompt string, songCount int) (*PlaylistResponse, error) {
-	if songCount <= 0 {
-		songCount = 20 // default
-	}
+	return s.GeneratePlaylistWithOptions(ctx, []string{prompt}, GenerateOptions{SongCount: songCount})
+}
 
-	systemPrompt := fmt.Sprintf(`You are a music curator AI. Your task is to create a playlist based on the user's prompt. 
+// GeneratePlaylistFromMultiplePrompts generates a playlist from multiple prompts
+func (s *Service) GeneratePlaylistFromMultiplePrompts(ctx context.Context, prompts []string, songCount int) (*PlaylistResponse, error) {
+	return s.GeneratePlaylistWithOptions(ctx, prompts, GenerateOptions{SongCount: songCount})
+}
 
-Please respond with a JSON object containing:
-- playlist_name: A creative name for the playlist
-- description: A brief description of the playlist theme
-- songs: An array of exactly %d songs, each with:
-  - artist: The artist name
-  - title: The song title
-  - album: The album name (optional)
-  - year: Release year (optional)
-  - reason: Brief reason why this song fits the theme (optional)
+// GeneratePlaylistWithOptions generates a playlist from one or more prompts with additional constraints
+func (s *Service) GeneratePlaylistWithOptions(ctx context.Context, prompts []string, opts GenerateOptions) (*PlaylistResponse, error) {
+	if len(prompts) == 0 {
+		return nil, fmt.Errorf("no prompts provided")
+	}
 
-Make sure the songs are diverse, well-known enough to be found on Spotify, and match the user's request. Focus on popular and recognizable tracks.
+	prompt := prompts[0]
+	if len(prompts) > 1 {
+		// Combine prompts into a single request
+		prompt = fmt.Sprintf("Create a playlist that combines these themes:\n%s", strings.Join(prompts, "\n- "))
+	}
 
-Respond only with valid JSON, no additional text.`, songCount)
+	systemPrompt := buildSystemPrompt(opts)
+	s.logger.Debug("requesting playlist from OpenAI",
+		"model", openai.GPT3Dot5Turbo,
+		"prompts", len(prompts),
+		"songs", opts.SongCount,
+		"system_prompt_chars", len(systemPrompt))
 
 	resp, err := s.client.CreateChatCompletion(
 		ctx,
@@ -90,6 +127,11 @@ Respond only with valid JSON, no additional text.`, songCount)
 		return nil, fmt.Errorf("no response from OpenAI")
 	}
 
+	s.logger.Debug("received OpenAI response",
+		"finish_reason", resp.Choices[0].FinishReason,
+		"prompt_tokens", resp.Usage.PromptTokens,
+		"completion_tokens", resp.Usage.CompletionTokens)
+
 	content := strings.TrimSpace(resp.Choices[0].Message.Content)
 
 	// Try to extract JSON from the response (in case there's extra text)
@@ -101,123 +143,113 @@ Respond only with valid JSON, no additional text.`, songCount)
 
 	var playlistResp PlaylistResponse
 	if err := json.Unmarshal([]byte(content), &playlistResp); err != nil {
+		s.logger.Debug("unparseable OpenAI response", "content", content)
 		return nil, fmt.Errorf("failed to parse OpenAI response: %w\nResponse: %s", err, content)
 	}
 
 	return &playlistResp, nil
 }
 
-// GeneratePlaylistFromMultiplePrompts generates a playlist from multiple prompts
-func (s *Service) GeneratePlaylistFromMultiplePrompts(ctx context.Context, prompts []string, songCount int) (*PlaylistResponse, error) {
-	if len(prompts) == 0 {
-		return nil, fmt.Errorf("no prompts provided")
+// buildSystemPrompt builds the curator instructions for the given options
+func buildSystemPrompt(opts GenerateOptions) string {
+	songCount := opts.SongCount
+	if songCount <= 0 {
+		songCount = 20 // default
 	}
 
-	if len(prompts) == 1 {
-		return s.GeneratePlaylist(ctx, prompts[0], songCount)
-	}
+	systemPrompt := fmt.Sprintf(`You are a music curator AI. Your task is to create a playlist based on the user's prompt. 
 
-	// Combine prompts into a single request
-	combinedPrompt := fmt.Sprintf("Create a playlist that combines these themes:\n%s", strings.Join(prompts, "\n- "))
+Please respond with a JSON object containing:
+- playlist_name: A creative name for the playlist
+- description: A brief description of the playlist theme
+- songs: An array of exactly %d songs, each with:
+  - artist: The artist name
+  - title: The song title
+  - album: The album name (optional)
+  - year: Release year (optional)
+  - reason: Brief reason why this song fits the theme (optional)
 
-	return s.GeneratePlaylist(ctx, combinedPrompt, songCount)
-}
+Make sure the songs are diverse, well-known enough to be found on Spotify, and match the user's request. Focus on popular and recognizable tracks.`, songCount)
 
-// LoadPlaylistFromFile loads a playlist from a text file
-func (s *Service) LoadPlaylistFromFile(filePath string, playlistName string) (*PlaylistResponse, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open file %s: %w", filePath, err)
+	if opts.Clean {
+		systemPrompt += `
+
+The playlist will be played in a workplace. Only include songs without explicit lyrics, or songs that are widely available in a clean/radio edit.`
+	}
+
+	if len(opts.SeedSongs) > 0 {
+		systemPrompt += "\n\n" + describeSeed(opts.SeedSongs)
 	}
-	defer file.Close()
 
-	var songs []Song
-	scanner := bufio.NewScanner(file)
+	if opts.TargetDuration > 0 {
+		systemPrompt += fmt.Sprintf(`
 
-	// Regex patterns to parse different song formats
-	patterns := []*regexp.Regexp{
-		// "Artist - Song Title"
-		regexp.MustCompile(`^(.+?)\s*-\s*(.+?)$`),
-		// "Artist: Song Title"
-		regexp.MustCompile(`^(.+?)\s*:\s*(.+?)$`),
-		// "Song Title by Artist"
-		regexp.MustCompile(`^(.+?)\s+by\s+(.+?)$`),
+The songs should add up to roughly %d minutes of music in total.`, int(opts.TargetDuration.Round(time.Minute).Minutes()))
 	}
 
-	lineNum := 0
-	for scanner.Scan() {
-		lineNum++
-		line := strings.TrimSpace(scanner.Text())
+	if len(opts.AvoidArtists) > 0 {
+		systemPrompt += fmt.Sprintf(`
 
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
-			continue
-		}
+Do not include any songs by these artists: %s.`, strings.Join(opts.AvoidArtists, ", "))
+	}
+
+	if len(opts.AvoidSongs) > 0 {
+		systemPrompt += `
 
-		var artist, title string
-		parsed := false
-
-		// Try each pattern
-		for _, pattern := range patterns {
-			matches := pattern.FindStringSubmatch(line)
-			if len(matches) == 3 {
-				if strings.Contains(line, " by ") {
-					// "Song Title by Artist" format
-					title = strings.TrimSpace(matches[1])
-					artist = strings.TrimSpace(matches[2])
-				} else {
-					// "Artist - Song Title" or "Artist: Song Title" format
-					artist = strings.TrimSpace(matches[1])
-					title = strings.TrimSpace(matches[2])
-				}
-				parsed = true
+Do not include any of these songs:`
+		for i, song := range opts.AvoidSongs {
+			if i >= maxAvoidSongs {
+				systemPrompt += fmt.Sprintf("\n- ... and %d more", len(opts.AvoidSongs)-maxAvoidSongs)
 				break
 			}
+			systemPrompt += fmt.Sprintf("\n- %s - %s", song.Artist, song.Title)
 		}
+	}
 
-		if !parsed {
-			// If no pattern matches, treat the whole line as a song title
-			// and we'll let Spotify search handle it
-			title = line
-			artist = "Unknown"
-		}
+	systemPrompt += `
 
-		songs = append(songs, Song{
-			Artist: artist,
-			Title:  title,
-			Reason: fmt.Sprintf("From file: %s (line %d)", filePath, lineNum),
-		})
-	}
+Respond only with valid JSON, no additional text.`
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading file %s: %w", filePath, err)
-	}
+	return systemPrompt
+}
 
-	if len(songs) == 0 {
-		return nil, fmt.Errorf("no songs found in file %s", filePath)
+// maxAvoidSongs limits how many songs to avoid are listed in the prompt;
+// anything beyond that is still filtered out after matching
+const maxAvoidSongs = 200
+
+// maxSeedSongs limits how many seed songs are listed in the prompt
+const maxSeedSongs = 50
+
+// describeSeed summarizes a seed playlist for the prompt: its most common
+// artists and a sample of its songs
+func describeSeed(songs []Song) string {
+	counts := make(map[string]int)
+	var artists []string
+	for _, song := range songs {
+		if counts[song.Artist] == 0 {
+			artists = append(artists, song.Artist)
+		}
+		counts[song.Artist]++
+	}
+	sort.SliceStable(artists, func(i, j int) bool {
+		return counts[artists[i]] > counts[artists[j]]
+	})
+	if len(artists) > 10 {
+		artists = artists[:10]
 	}
 
-	// Use provided playlist name or derive from filename
-	if playlistName == "" {
-		playlistName = strings.TrimSuffix(strings.TrimSuffix(filePath, ".txt"), ".list")
-		// Remove path and clean up the name
-		if lastSlash := strings.LastIndex(playlistName, "/"); lastSlash != -1 {
-			playlistName = playlistName[lastSlash+1:]
-		}
-		if lastSlash := strings.LastIndex(playlistName, "\\"); lastSlash != -1 {
-			playlistName = playlistName[lastSlash+1:]
+	var b strings.Builder
+	fmt.Fprintf(&b, "The user wants more songs like an existing playlist of %d songs. ", len(songs))
+	fmt.Fprintf(&b, "Its most frequent artists are: %s.\n", strings.Join(artists, ", "))
+	b.WriteString("Songs in the playlist include:")
+	for i, song := range songs {
+		if i >= maxSeedSongs {
+			fmt.Fprintf(&b, "\n- ... and %d more", len(songs)-maxSeedSongs)
+			break
 		}
-		playlistName = strings.ReplaceAll(playlistName, "_", " ")
-		playlistName = strings.ReplaceAll(playlistName, "-", " ")
-		playlistName = strings.Title(playlistName)
-
-		// Add timestamp to avoid duplicates when no custom name provided
-		playlistName = fmt.Sprintf("%s (%s)", playlistName, time.Now().Format("Jan 2, 2006"))
+		fmt.Fprintf(&b, "\n- %s - %s", song.Artist, song.Title)
 	}
+	b.WriteString("\nSuggest songs that match its style and mood. Do not include songs that are already in it.")
 
-	return &PlaylistResponse{
-		PlaylistName: playlistName,
-		Description:  fmt.Sprintf("Playlist loaded from %s (%d songs)", filePath, len(songs)),
-		Songs:        songs,
-	}, nil
+	return b.String()
 }
@@ -3,10 +3,10 @@ package openai
 import (
 	"context"
 	"encoding/json"
-	"os"
-	"path/filepath"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -20,160 +20,6 @@ func TestNewService(t *testing.T) {
 	assert.NotNil(t, service.client)
 }
 
-func TestLoadPlaylistFromFile_Success(t *testing.T) {
-	// Create a temporary test file
-	content := `# Test playlist
-Metallica - Master of Puppets
-Iron Maiden: Run to the Hills
-Enter Sandman by Metallica
-Just a Song Title
-
-// Another comment
-AC/DC - Thunderstruck`
-
-	tmpFile := createTempFile(t, "test-playlist.txt", content)
-	defer os.Remove(tmpFile)
-
-	service := NewService("test-key")
-
-	result, err := service.LoadPlaylistFromFile(tmpFile, "My Test Playlist")
-
-	require.NoError(t, err)
-	assert.Equal(t, "My Test Playlist", result.PlaylistName)
-	assert.Contains(t, result.Description, "test-playlist.txt")
-	assert.Contains(t, result.Description, "5 songs")
-	assert.Len(t, result.Songs, 5)
-
-	// Test different song formats
-	songs := result.Songs
-
-	// "Artist - Song Title"
-	assert.Equal(t, "Metallica", songs[0].Artist)
-	assert.Equal(t, "Master of Puppets", songs[0].Title)
-
-	// "Artist: Song Title"
-	assert.Equal(t, "Iron Maiden", songs[1].Artist)
-	assert.Equal(t, "Run to the Hills", songs[1].Title)
-
-	// "Song Title by Artist"
-	assert.Equal(t, "Metallica", songs[2].Artist)
-	assert.Equal(t, "Enter Sandman", songs[2].Title)
-
-	// Just song title
-	assert.Equal(t, "Unknown", songs[3].Artist)
-	assert.Equal(t, "Just a Song Title", songs[3].Title)
-
-	// Another "Artist - Song Title"
-	assert.Equal(t, "AC/DC", songs[4].Artist)
-	assert.Equal(t, "Thunderstruck", songs[4].Title)
-
-	// Check reasons contain file path and line numbers
-	for _, song := range songs {
-		assert.Contains(t, song.Reason, "test-playlist.txt")
-		assert.Contains(t, song.Reason, "line")
-	}
-}
-
-func TestLoadPlaylistFromFile_AutoGeneratedName(t *testing.T) {
-	content := `Metallica - Master of Puppets
-Iron Maiden - Run to the Hills`
-
-	tmpFile := createTempFile(t, "metal-songs.txt", content)
-	defer os.Remove(tmpFile)
-
-	service := NewService("test-key")
-
-	result, err := service.LoadPlaylistFromFile(tmpFile, "")
-
-	require.NoError(t, err)
-	assert.Contains(t, result.PlaylistName, "Metal Songs")
-	// Should contain current year (2024 or 2025)
-	assert.True(t, strings.Contains(result.PlaylistName, "2024") || strings.Contains(result.PlaylistName, "2025"))
-}
-
-func TestLoadPlaylistFromFile_FileNotFound(t *testing.T) {
-	service := NewService("test-key")
-
-	result, err := service.LoadPlaylistFromFile("nonexistent.txt", "Test")
-
-	assert.Nil(t, result)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "failed to open file")
-}
-
-func TestLoadPlaylistFromFile_EmptyFile(t *testing.T) {
-	content := `# Only comments
-// Nothing else`
-
-	tmpFile := createTempFile(t, "empty.txt", content)
-	defer os.Remove(tmpFile)
-
-	service := NewService("test-key")
-
-	result, err := service.LoadPlaylistFromFile(tmpFile, "Test")
-
-	assert.Nil(t, result)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "no songs found")
-}
-
-func TestLoadPlaylistFromFile_DifferentFormats(t *testing.T) {
-	tests := []struct {
-		name           string
-		line           string
-		expectedArtist string
-		expectedTitle  string
-	}{
-		{
-			name:           "dash format",
-			line:           "The Beatles - Hey Jude",
-			expectedArtist: "The Beatles",
-			expectedTitle:  "Hey Jude",
-		},
-		{
-			name:           "colon format",
-			line:           "Queen: Bohemian Rhapsody",
-			expectedArtist: "Queen",
-			expectedTitle:  "Bohemian Rhapsody",
-		},
-		{
-			name:           "by format",
-			line:           "Stairway to Heaven by Led Zeppelin",
-			expectedArtist: "Led Zeppelin",
-			expectedTitle:  "Stairway to Heaven",
-		},
-		{
-			name:           "title only",
-			line:           "Hotel California",
-			expectedArtist: "Unknown",
-			expectedTitle:  "Hotel California",
-		},
-		{
-			name:           "with extra spaces",
-			line:           "  Pink Floyd   -   Comfortably Numb  ",
-			expectedArtist: "Pink Floyd",
-			expectedTitle:  "Comfortably Numb",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			tmpFile := createTempFile(t, "test.txt", tt.line)
-			defer os.Remove(tmpFile)
-
-			service := NewService("test-key")
-			result, err := service.LoadPlaylistFromFile(tmpFile, "Test")
-
-			require.NoError(t, err)
-			require.Len(t, result.Songs, 1)
-
-			song := result.Songs[0]
-			assert.Equal(t, tt.expectedArtist, song.Artist)
-			assert.Equal(t, tt.expectedTitle, song.Title)
-		})
-	}
-}
-
 func TestGeneratePlaylistFromMultiplePrompts_SinglePrompt(t *testing.T) {
 	service := NewService("test-key")
 
@@ -210,6 +56,80 @@ func TestGeneratePlaylistFromMultiplePrompts_MultiplePrompts(t *testing.T) {
 	assert.Nil(t, result)
 }
 
+func TestGeneratePlaylistWithOptions_NoPrompts(t *testing.T) {
+	service := NewService("test-key")
+
+	result, err := service.GeneratePlaylistWithOptions(context.Background(), nil, GenerateOptions{Clean: true})
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "no prompts provided")
+}
+
+func TestBuildSystemPrompt(t *testing.T) {
+	prompt := buildSystemPrompt(GenerateOptions{})
+	assert.Contains(t, prompt, "exactly 20 songs")
+	assert.NotContains(t, prompt, "explicit")
+	assert.True(t, strings.HasSuffix(prompt, "Respond only with valid JSON, no additional text."))
+
+	prompt = buildSystemPrompt(GenerateOptions{SongCount: 12, Clean: true})
+	assert.Contains(t, prompt, "exactly 12 songs")
+	assert.Contains(t, prompt, "without explicit lyrics")
+	assert.True(t, strings.HasSuffix(prompt, "Respond only with valid JSON, no additional text."))
+
+	prompt = buildSystemPrompt(GenerateOptions{
+		SongCount:      15,
+		TargetDuration: 45 * time.Minute,
+		AvoidSongs:     []Song{{Artist: "Metallica", Title: "One"}},
+		AvoidArtists:   []string{"Nickelback", "Creed"},
+	})
+	assert.Contains(t, prompt, "roughly 45 minutes")
+	assert.Contains(t, prompt, "- Metallica - One")
+	assert.Contains(t, prompt, "Do not include any songs by these artists: Nickelback, Creed.")
+	assert.True(t, strings.HasSuffix(prompt, "Respond only with valid JSON, no additional text."))
+}
+
+func TestBuildSystemPrompt_LimitsAvoidSongs(t *testing.T) {
+	var avoid []Song
+	for i := 0; i < maxAvoidSongs+5; i++ {
+		avoid = append(avoid, Song{Artist: "Artist", Title: fmt.Sprintf("Song %d", i)})
+	}
+
+	prompt := buildSystemPrompt(GenerateOptions{AvoidSongs: avoid})
+
+	assert.Contains(t, prompt, fmt.Sprintf("- Artist - Song %d", maxAvoidSongs-1))
+	assert.NotContains(t, prompt, fmt.Sprintf("- Artist - Song %d", maxAvoidSongs))
+	assert.Contains(t, prompt, "- ... and 5 more")
+}
+
+func TestDescribeSeed(t *testing.T) {
+	songs := []Song{
+		{Artist: "Iron Maiden", Title: "The Trooper"},
+		{Artist: "Metallica", Title: "One"},
+		{Artist: "Metallica", Title: "Battery"},
+	}
+
+	description := describeSeed(songs)
+
+	assert.Contains(t, description, "existing playlist of 3 songs")
+	assert.Contains(t, description, "most frequent artists are: Metallica, Iron Maiden.")
+	assert.Contains(t, description, "- Iron Maiden - The Trooper")
+	assert.Contains(t, description, "- Metallica - Battery")
+	assert.Contains(t, buildSystemPrompt(GenerateOptions{SeedSongs: songs}), description)
+}
+
+func TestDescribeSeed_LimitsSongs(t *testing.T) {
+	var songs []Song
+	for i := 0; i < maxSeedSongs+5; i++ {
+		songs = append(songs, Song{Artist: "Artist", Title: fmt.Sprintf("Song %d", i)})
+	}
+
+	description := describeSeed(songs)
+
+	assert.Contains(t, description, "- Artist - Song 49")
+	assert.NotContains(t, description, "- Artist - Song 50")
+	assert.Contains(t, description, "... and 5 more")
+}
+
 func TestPlaylistResponse_JSONSerialization(t *testing.T) {
 	// Test that our structs can be properly marshaled/unmarshaled
 	originalResponse := &PlaylistResponse{
@@ -269,35 +189,8 @@ func TestSong_OptionalFields(t *testing.T) {
 	assert.Empty(t, parsedSong.Reason)
 }
 
-// Helper function to create temporary test files
-func createTempFile(t *testing.T, name, content string) string {
-	tmpDir := t.TempDir()
-	filePath := filepath.Join(tmpDir, name)
-
-	err := os.WriteFile(filePath, []byte(content), 0644)
-	require.NoError(t, err)
-
-	return filePath
-}
-
-// Benchmark tests for file parsing performance
-func BenchmarkLoadPlaylistFromFile(b *testing.B) {
-	// Create a large test file
-	var lines []string
-	for i := 0; i < 1000; i++ {
-		lines = append(lines, "Artist "+string(rune(i%26+65))+" - Song "+string(rune(i%26+65)))
-	}
-	content := strings.Join(lines, "\n")
-
-	tmpFile := filepath.Join(b.TempDir(), "benchmark.txt")
-	err := os.WriteFile(tmpFile, []byte(content), 0644)
-	require.NoError(b, err)
-
-	service := NewService("test-key")
-
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		_, err := service.LoadPlaylistFromFile(tmpFile, "Benchmark")
-		require.NoError(b, err)
-	}
+func TestSong_String(t *testing.T) {
+	assert.Equal(t, "Toto - Africa", Song{Artist: "Toto", Title: "Africa"}.String())
+	assert.Equal(t, "Africa", Song{Title: "Africa"}.String())
+	assert.Equal(t, "spotify:track:abc", Song{URI: "spotify:track:abc"}.String())
 }
@@ -0,0 +1,217 @@
+package overrides
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"auto-spotify/internal/songkey"
+	"auto-spotify/internal/statefile"
+)
+
+// SkipTarget is the override target that excludes a song from playlists
+const SkipTarget = "skip"
+
+// Override pins a song to a specific Spotify track, or skips it entirely
+type Override struct {
+	Artist string
+	Title  string
+	Target string // Spotify track URI/URL/ID, or SkipTarget
+}
+
+// Skip reports whether the override excludes the song
+func (o Override) Skip() bool {
+	return strings.EqualFold(o.Target, SkipTarget)
+}
+
+// Overrides holds manual match corrections loaded from an overrides file.
+// A nil *Overrides is valid and never matches anything.
+//
+// File format, one override per line:
+//
+//	Artist - Song Title => spotify:track:4uLU6hMCjMI75M1A2tKUQC
+//	Artist - Song Title => skip
+//	Song Title => skip
+//
+// A song without an artist is written as its title alone, or as
+// "- Song Title" when the title itself contains " - ".
+type Overrides struct {
+	path    string
+	entries map[string]Override
+	mu      sync.Mutex
+}
+
+// DefaultPath returns the overrides file location inside the user config directory
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user config directory: %w", err)
+	}
+	return filepath.Join(dir, "auto-spotify", "overrides.txt"), nil
+}
+
+// Load reads the overrides file at path, starting empty if the file doesn't exist yet
+func Load(path string) (*Overrides, error) {
+	o := &Overrides{
+		path:    path,
+		entries: make(map[string]Override),
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return o, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open overrides file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+
+		// Skip empty lines and comments
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		override, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid override in %s (line %d): %w", path, lineNum, err)
+		}
+		o.entries[songkey.Exact(override.Artist, override.Title)] = override
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading overrides file %s: %w", path, err)
+	}
+
+	return o, nil
+}
+
+// parseLine parses "Artist - Song Title => target", or "Song Title => target"
+// and "- Song Title => target" for songs without an artist
+func parseLine(line string) (Override, error) {
+	idx := strings.LastIndex(line, "=>")
+	if idx == -1 {
+		return Override{}, fmt.Errorf("missing '=>' in %q", line)
+	}
+
+	song := strings.TrimSpace(line[:idx])
+	target := strings.TrimSpace(line[idx+2:])
+	if target == "" {
+		return Override{}, fmt.Errorf("missing target in %q", line)
+	}
+
+	if title, ok := strings.CutPrefix(song, "- "); ok {
+		song = " - " + title
+	}
+	artist, title, found := strings.Cut(song, " - ")
+	if !found {
+		artist, title = "", song
+	}
+
+	override := Override{
+		Artist: strings.TrimSpace(artist),
+		Title:  strings.TrimSpace(title),
+		Target: target,
+	}
+	if override.Title == "" {
+		return Override{}, fmt.Errorf("missing song title in %q", line)
+	}
+	return override, nil
+}
+
+// Lookup returns the override for the song, if any
+func (o *Overrides) Lookup(artist, title string) (Override, bool) {
+	if o == nil {
+		return Override{}, false
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	override, ok := o.entries[songkey.Exact(artist, title)]
+	return override, ok
+}
+
+// Set adds or replaces the override for the song
+func (o *Overrides) Set(artist, title, target string) {
+	if o == nil {
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.entries[songkey.Exact(artist, title)] = Override{
+		Artist: artist,
+		Title:  title,
+		Target: target,
+	}
+}
+
+// Len returns the number of overrides
+func (o *Overrides) Len() int {
+	if o == nil {
+		return 0
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return len(o.entries)
+}
+
+// Path returns the location of the overrides file
+func (o *Overrides) Path() string {
+	if o == nil {
+		return ""
+	}
+	return o.path
+}
+
+// Save writes all overrides back to the file, sorted by artist and title
+func (o *Overrides) Save() error {
+	if o == nil {
+		return nil
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	keys := make([]string, 0, len(o.entries))
+	for k := range o.entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("# Auto-Spotify match overrides\n")
+	b.WriteString("# Format: Artist - Song Title => spotify:track:ID (or \"skip\")\n\n")
+	for _, k := range keys {
+		override := o.entries[k]
+		fmt.Fprintf(&b, "%s => %s\n", formatSong(override), override.Target)
+	}
+
+	return statefile.Write(o.path, "overrides", []byte(b.String()))
+}
+
+// formatSong writes the song of an override the way parseLine reads it back
+func formatSong(override Override) string {
+	switch {
+	case override.Artist != "":
+		return override.Artist + " - " + override.Title
+	case strings.Contains(override.Title, " - "):
+		return "- " + override.Title
+	default:
+		return override.Title
+	}
+}
@@ -0,0 +1,149 @@
+package overrides
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Success(t *testing.T) {
+	content := `# Match overrides
+Metallica - One => spotify:track:4uLU6hMCjMI75M1A2tKUQC
+Iron Maiden - Run to the Hills => skip
+
+// Another comment
+AC/DC - Thunderstruck => https://open.spotify.com/track/57bgtoPSgt236HzfBOd8kj`
+
+	path := filepath.Join(t.TempDir(), "overrides.txt")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	o, err := Load(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, o.Len())
+
+	override, ok := o.Lookup("metallica", "ONE")
+	require.True(t, ok)
+	assert.Equal(t, "spotify:track:4uLU6hMCjMI75M1A2tKUQC", override.Target)
+	assert.False(t, override.Skip())
+
+	override, ok = o.Lookup("Iron Maiden", "Run to the Hills")
+	require.True(t, ok)
+	assert.True(t, override.Skip())
+
+	_, ok = o.Lookup("Metallica", "Enter Sandman")
+	assert.False(t, ok)
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	o, err := Load(filepath.Join(t.TempDir(), "missing.txt"))
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, o.Len())
+}
+
+func TestLoad_InvalidLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		errorMsg string
+	}{
+		{name: "missing arrow", line: "Metallica - One", errorMsg: "missing '=>'"},
+		{name: "missing target", line: "Metallica - One =>", errorMsg: "missing target"},
+		{name: "missing song", line: "=> skip", errorMsg: "missing song title"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "overrides.txt")
+			require.NoError(t, os.WriteFile(path, []byte(tt.line), 0644))
+
+			o, err := Load(path)
+
+			assert.Nil(t, o)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "line 1")
+			assert.Contains(t, err.Error(), tt.errorMsg)
+		})
+	}
+}
+
+func TestOverrides_SetAndSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "overrides.txt")
+	o, err := Load(path)
+	require.NoError(t, err)
+
+	o.Set("Queen", "Under Pressure", "spotify:track:2fuCquhmrzHpu5xcA1ci9x")
+	o.Set("Metallica", "One", SkipTarget)
+	require.NoError(t, o.Save())
+
+	reloaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, reloaded.Len())
+
+	override, ok := reloaded.Lookup("Queen", "Under Pressure")
+	require.True(t, ok)
+	assert.Equal(t, "spotify:track:2fuCquhmrzHpu5xcA1ci9x", override.Target)
+
+	override, ok = reloaded.Lookup("Metallica", "One")
+	require.True(t, ok)
+	assert.True(t, override.Skip())
+}
+
+func TestOverrides_SaveTitleOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.txt")
+	o, err := Load(path)
+	require.NoError(t, err)
+
+	o.Set("", "Intro", SkipTarget)
+	o.Set("", "Symphony No. 5 - Allegro", "spotify:track:2fuCquhmrzHpu5xcA1ci9x")
+	o.Set("Metallica", "One", SkipTarget)
+	require.NoError(t, o.Save())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "\nIntro => skip\n")
+	assert.Contains(t, string(data), "\n- Symphony No. 5 - Allegro => spotify:track:2fuCquhmrzHpu5xcA1ci9x\n")
+
+	reloaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, 3, reloaded.Len())
+
+	override, ok := reloaded.Lookup("", "Intro")
+	require.True(t, ok)
+	assert.True(t, override.Skip())
+
+	override, ok = reloaded.Lookup("", "Symphony No. 5 - Allegro")
+	require.True(t, ok)
+	assert.Equal(t, "spotify:track:2fuCquhmrzHpu5xcA1ci9x", override.Target)
+
+	_, ok = reloaded.Lookup("Metallica", "One")
+	assert.True(t, ok)
+}
+
+func TestLoad_EmptyArtistLine(t *testing.T) {
+	// Older versions wrote songs without an artist as " - Title"
+	path := filepath.Join(t.TempDir(), "overrides.txt")
+	require.NoError(t, os.WriteFile(path, []byte(" - Intro => skip\n"), 0644))
+
+	o, err := Load(path)
+	require.NoError(t, err)
+
+	override, ok := o.Lookup("", "Intro")
+	require.True(t, ok)
+	assert.True(t, override.Skip())
+}
+
+func TestOverrides_NilNeverMatches(t *testing.T) {
+	var o *Overrides
+
+	o.Set("Metallica", "One", SkipTarget)
+	_, ok := o.Lookup("Metallica", "One")
+
+	assert.False(t, ok)
+	assert.Equal(t, 0, o.Len())
+	assert.NoError(t, o.Save())
+}
@@ -0,0 +1,410 @@
+package playlistfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"auto-spotify/internal/spotifyid"
+)
+
+func init() {
+	Register(Format{
+		Name:       "csv",
+		Extensions: []string{".csv"},
+		Reader:     CSVReader{},
+		Writer:     CSVWriter{},
+	})
+	Register(Format{
+		Name:       "tsv",
+		Extensions: []string{".tsv", ".tab"},
+		Reader:     CSVReader{Comma: '\t'},
+		Writer:     CSVWriter{Comma: '\t'},
+	})
+}
+
+// Song fields a CSV column can be mapped to
+const (
+	fieldArtist  = "artist"
+	fieldArtists = "artists"
+	fieldTitle   = "title"
+	fieldAlbum   = "album"
+	fieldYear    = "year"
+	fieldURI     = "uri"
+	fieldReason  = "reason"
+)
+
+// columnAliases are the headers recognized for each field, lowercase
+var columnAliases = map[string][]string{
+	fieldArtist:  {"artist", "artist name", "band", "performer", "singer"},
+	fieldArtists: {"artists", "artist name(s)", "artist names"},
+	fieldTitle:   {"title", "track", "track name", "song", "song title", "name"},
+	fieldAlbum:   {"album", "album name", "release"},
+	fieldYear:    {"year", "release year", "release date", "album release date", "date"},
+	fieldURI:     {"uri", "spotify uri", "track uri", "spotify id", "id", "url", "link", "spotify url"},
+	fieldReason:  {"reason", "notes", "note", "comment"},
+}
+
+// csvColumns are the columns CSVWriter writes, in order
+var csvColumns = []string{"ID", "Title", "Artists", "Album", "Year", "Duration", "ISRC", "Popularity", "Added At"}
+
+// CSVReader reads spreadsheet exports. A header row is recognized by its
+// column names, like "Artist" and "Title" or the names set in Columns.
+// Without a header the columns are artist, title, album and year, and a
+// single column holds text playlist lines.
+type CSVReader struct {
+	Comma   rune              // Field separator, detected between ',' and ';' when zero
+	Columns map[string]string // Song field to column header or 1-based index
+}
+
+func (c CSVReader) withOptions(opts Options) (Reader, error) {
+	for field := range opts.Columns {
+		if _, ok := columnAliases[field]; !ok {
+			return nil, fmt.Errorf("unknown column field '%s' (supported: %s)", field, strings.Join(columnFields(), ", "))
+		}
+	}
+	c.Columns = opts.Columns
+	return c, nil
+}
+
+// columnFields returns the fields columns can be mapped to
+func columnFields() []string {
+	fields := make([]string, 0, len(columnAliases))
+	for field := range columnAliases {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// Read parses the songs in r
+func (c CSVReader) Read(r io.Reader) (*Playlist, error) {
+	br := bufio.NewReader(r)
+	comma := c.Comma
+	if comma == 0 {
+		comma = sniffComma(br)
+	}
+
+	reader := csv.NewReader(br)
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	playlist := &Playlist{}
+	var columns map[string]int
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		if columns == nil {
+			columns, err = c.headerColumns(record)
+			if err != nil {
+				return nil, err
+			}
+			if columns != nil {
+				continue
+			}
+			columns, err = c.positionalColumns(record)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if isBlank(record) || strings.HasPrefix(strings.TrimSpace(record[0]), "#") {
+			continue
+		}
+
+		track, warning := csvTrack(record, columns)
+		if warning != "" {
+			playlist.Warnings = append(playlist.Warnings, Warning{Line: line, Message: warning})
+		}
+		if track.Title == "" && track.URI == "" {
+			playlist.Warnings = append(playlist.Warnings, Warning{Line: line, Message: "no title or track URI, skipping the row"})
+			continue
+		}
+		track.Line = line
+		playlist.Tracks = append(playlist.Tracks, track)
+	}
+
+	return playlist, nil
+}
+
+// headerColumns maps fields to column positions if the record is a header
+// row, and returns nil if it isn't
+func (c CSVReader) headerColumns(record []string) (map[string]int, error) {
+	positions := make(map[string]int)
+	for i, cell := range record {
+		positions[strings.ToLower(strings.TrimSpace(cell))] = i
+	}
+
+	columns := make(map[string]int)
+	for field, column := range c.Columns {
+		if _, isIndex := columnIndex(column); isIndex {
+			continue
+		}
+		i, ok := positions[strings.ToLower(strings.TrimSpace(column))]
+		if !ok {
+			return nil, fmt.Errorf("column '%s' for %s not found in the header: %s", column, field, strings.Join(record, ", "))
+		}
+		columns[field] = i
+	}
+
+	// Columns mapped by name make this the header; otherwise it takes a
+	// recognized title or artist column
+	if len(columns) == 0 {
+		headerFound := false
+		for _, field := range []string{fieldTitle, fieldArtist, fieldArtists, fieldURI} {
+			if _, ok := lookupAlias(positions, field); ok {
+				headerFound = true
+			}
+		}
+		if !headerFound {
+			return nil, nil
+		}
+	}
+
+	for field := range columnAliases {
+		if _, mapped := c.Columns[field]; mapped {
+			continue
+		}
+		if i, ok := lookupAlias(positions, field); ok {
+			columns[field] = i
+		}
+	}
+	if err := c.applyIndexes(columns); err != nil {
+		return nil, err
+	}
+
+	if _, ok := columns[fieldTitle]; !ok {
+		if _, ok := columns[fieldURI]; !ok {
+			return nil, fmt.Errorf("no title or track URI column in the header: %s (map one with --columns title=<column>)", strings.Join(record, ", "))
+		}
+	}
+	return columns, nil
+}
+
+// positionalColumns maps fields for files without a header row
+func (c CSVReader) positionalColumns(record []string) (map[string]int, error) {
+	columns := make(map[string]int)
+	if len(c.Columns) == 0 {
+		if len(record) == 1 {
+			// A single column holds text playlist lines
+			return map[string]int{"": 0}, nil
+		}
+		for i, field := range []string{fieldArtist, fieldTitle, fieldAlbum, fieldYear} {
+			columns[field] = i
+		}
+		return columns, nil
+	}
+
+	for field, column := range c.Columns {
+		if _, isIndex := columnIndex(column); !isIndex {
+			return nil, fmt.Errorf("column '%s' for %s not found, the file has no header row", column, field)
+		}
+	}
+	if err := c.applyIndexes(columns); err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
+
+// applyIndexes adds the columns mapped by index
+func (c CSVReader) applyIndexes(columns map[string]int) error {
+	for field, column := range c.Columns {
+		if i, isIndex := columnIndex(column); isIndex {
+			if i < 0 {
+				return fmt.Errorf("column index for %s must be 1 or more, got %s", field, column)
+			}
+			columns[field] = i
+		}
+	}
+	return nil
+}
+
+// columnIndex parses a 1-based column index
+func columnIndex(column string) (int, bool) {
+	n, err := strconv.Atoi(strings.TrimSpace(column))
+	if err != nil {
+		return 0, false
+	}
+	return n - 1, true
+}
+
+func lookupAlias(positions map[string]int, field string) (int, bool) {
+	for _, alias := range columnAliases[field] {
+		if i, ok := positions[alias]; ok {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// csvTrack builds a track from a row
+func csvTrack(record []string, columns map[string]int) (Track, string) {
+	cell := func(field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	// A single column of text playlist lines
+	if i, ok := columns[""]; ok {
+		return parseLine(strings.TrimSpace(record[i]))
+	}
+
+	track := Track{
+		Artist: cell(fieldArtist),
+		Title:  cell(fieldTitle),
+		Album:  cell(fieldAlbum),
+		Reason: cell(fieldReason),
+	}
+
+	if artists := cell(fieldArtists); artists != "" {
+		track.Artists = splitArtists(artists)
+		if track.Artist == "" {
+			track.Artist = track.Artists[0]
+		}
+	}
+
+	var warnings []string
+	if year := cell(fieldYear); year != "" {
+		// Release dates like 1986-03-01 start with the year
+		if len(year) >= 4 && yearPattern.MatchString(year[:4]) {
+			track.Year, _ = strconv.Atoi(year[:4])
+		} else {
+			warnings = append(warnings, fmt.Sprintf("'%s' is not a year", year))
+		}
+	}
+
+	if uri := cell(fieldURI); uri != "" {
+		if id, err := spotifyid.Parse("track", uri); err == nil {
+			track.URI = trackURIPrefix + id
+		} else {
+			warnings = append(warnings, fmt.Sprintf("'%s' is not a Spotify track URI, URL or ID", uri))
+		}
+	}
+
+	return track, strings.Join(warnings, "; ")
+}
+
+// artistSeparator separates the artists in the artists column. Artist names
+// can contain commas ("Crosby, Stills, Nash & Young"), but not semicolons.
+const artistSeparator = "; "
+
+// splitArtists splits a list of artists written as "A; B"
+func splitArtists(s string) []string {
+	var artists []string
+	for _, artist := range strings.Split(s, strings.TrimSpace(artistSeparator)) {
+		if artist = strings.TrimSpace(artist); artist != "" {
+			artists = append(artists, artist)
+		}
+	}
+	if len(artists) == 0 {
+		return []string{s}
+	}
+	return artists
+}
+
+func isBlank(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// sniffComma picks ';' over ',' when the first line has more of them, as
+// spreadsheets do in locales that write decimals with a comma
+func sniffComma(br *bufio.Reader) rune {
+	peek, _ := br.Peek(4096)
+	if i := bytes.IndexByte(peek, '\n'); i >= 0 {
+		peek = peek[:i]
+	}
+	if bytes.Count(peek, []byte(";")) > bytes.Count(peek, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+// CSVWriter writes one row per track with the track's Spotify details
+type CSVWriter struct {
+	Comma rune // Field separator, ',' when zero
+}
+
+// Write writes the playlist to w
+func (c CSVWriter) Write(w io.Writer, playlist *Playlist) error {
+	writer := csv.NewWriter(w)
+	if c.Comma != 0 {
+		writer.Comma = c.Comma
+	}
+
+	if err := writer.Write(csvColumns); err != nil {
+		return err
+	}
+
+	for _, track := range playlist.Tracks {
+		artists := track.Artists
+		if len(artists) == 0 && track.Artist != "" {
+			artists = []string{track.Artist}
+		}
+
+		row := []string{
+			strings.TrimPrefix(track.URI, trackURIPrefix),
+			track.Title,
+			strings.Join(artists, artistSeparator),
+			track.Album,
+			"",
+			"",
+			track.ISRC,
+			"",
+			"",
+		}
+		if track.Year != 0 {
+			row[4] = strconv.Itoa(track.Year)
+		}
+		if track.Duration > 0 {
+			row[5] = formatTrackDuration(track.Duration)
+		}
+		if track.URI != "" {
+			// Only Spotify tracks have a popularity, and 0 is a valid one
+			row[7] = strconv.Itoa(track.Popularity)
+		}
+		if !track.AddedAt.IsZero() {
+			row[8] = track.AddedAt.UTC().Format(time.RFC3339)
+		}
+
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatTrackDuration formats a track length as "4:35" or "1:02:10"
+func formatTrackDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d >= time.Hour {
+		return fmt.Sprintf("%d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+	}
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
@@ -0,0 +1,161 @@
+package playlistfile
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVReader_Header(t *testing.T) {
+	content := `Title,Artist,Album,Release Date,Notes
+Africa,Toto,Toto IV,1982-04-08,Sing-along
+"One More Time","Daft Punk",Discovery,2000,
+,,,,
+# Skipped,Nobody,,,
+Hey Jude,The Beatles,,sometime,
+`
+
+	playlist, err := CSVReader{}.Read(strings.NewReader(content))
+
+	require.NoError(t, err)
+	require.Len(t, playlist.Tracks, 3)
+	assert.Equal(t, Track{Artist: "Toto", Title: "Africa", Album: "Toto IV", Year: 1982, Reason: "Sing-along", Line: 2}, playlist.Tracks[0])
+	assert.Equal(t, "Daft Punk", playlist.Tracks[1].Artist)
+	assert.Equal(t, 2000, playlist.Tracks[1].Year)
+	assert.Equal(t, 6, playlist.Tracks[2].Line)
+
+	require.Len(t, playlist.Warnings, 1)
+	assert.Equal(t, "line 6: 'sometime' is not a year", playlist.Warnings[0].String())
+}
+
+func TestCSVReader_NoHeader(t *testing.T) {
+	playlist, err := CSVReader{}.Read(strings.NewReader("Toto,Africa,Toto IV,1982\nDaft Punk,One More Time\n"))
+
+	require.NoError(t, err)
+	require.Len(t, playlist.Tracks, 2)
+	assert.Equal(t, Track{Artist: "Toto", Title: "Africa", Album: "Toto IV", Year: 1982, Line: 1}, playlist.Tracks[0])
+	assert.Equal(t, "One More Time", playlist.Tracks[1].Title)
+}
+
+func TestCSVReader_SingleColumn(t *testing.T) {
+	playlist, err := CSVReader{}.Read(strings.NewReader("Toto - Africa [1982]\nspotify:track:7ouMYWpwJ422jRcDASZB7P\n"))
+
+	require.NoError(t, err)
+	require.Len(t, playlist.Tracks, 2)
+	assert.Equal(t, "Toto", playlist.Tracks[0].Artist)
+	assert.Equal(t, 1982, playlist.Tracks[0].Year)
+	assert.Equal(t, "spotify:track:7ouMYWpwJ422jRcDASZB7P", playlist.Tracks[1].URI)
+}
+
+func TestCSVReader_Semicolons(t *testing.T) {
+	playlist, err := CSVReader{}.Read(strings.NewReader("Artist;Song;Rating\nToto;Africa;4,5\n"))
+
+	require.NoError(t, err)
+	require.Len(t, playlist.Tracks, 1)
+	assert.Equal(t, "Toto", playlist.Tracks[0].Artist)
+	assert.Equal(t, "Africa", playlist.Tracks[0].Title)
+}
+
+func TestCSVReader_TSV(t *testing.T) {
+	playlist, err := CSVReader{Comma: '\t'}.Read(strings.NewReader("artist\ttitle\nEarth, Wind & Fire\tSeptember\n"))
+
+	require.NoError(t, err)
+	require.Len(t, playlist.Tracks, 1)
+	assert.Equal(t, "Earth, Wind & Fire", playlist.Tracks[0].Artist)
+}
+
+func TestCSVReader_ColumnMapping(t *testing.T) {
+	reader, err := CSVReader{}.withOptions(Options{Columns: map[string]string{"artist": "Band", "title": "Song"}})
+	require.NoError(t, err)
+
+	playlist, err := reader.Read(strings.NewReader("Rank,Song,Band,Track\n1,Africa,Toto,A1\n"))
+
+	require.NoError(t, err)
+	require.Len(t, playlist.Tracks, 1)
+	assert.Equal(t, "Toto", playlist.Tracks[0].Artist)
+	assert.Equal(t, "Africa", playlist.Tracks[0].Title)
+}
+
+func TestCSVReader_ColumnIndexes(t *testing.T) {
+	reader, err := CSVReader{}.withOptions(Options{Columns: map[string]string{"title": "3", "artist": "1"}})
+	require.NoError(t, err)
+
+	playlist, err := reader.Read(strings.NewReader("Toto,x,Africa\n"))
+
+	require.NoError(t, err)
+	require.Len(t, playlist.Tracks, 1)
+	assert.Equal(t, "Toto", playlist.Tracks[0].Artist)
+	assert.Equal(t, "Africa", playlist.Tracks[0].Title)
+}
+
+func TestCSVReader_ColumnErrors(t *testing.T) {
+	_, err := CSVReader{}.withOptions(Options{Columns: map[string]string{"genre": "Genre"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown column field 'genre'")
+
+	reader, err := CSVReader{}.withOptions(Options{Columns: map[string]string{"title": "Song"}})
+	require.NoError(t, err)
+	_, err = reader.Read(strings.NewReader("Name,Band\nAfrica,Toto\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "column 'Song' for title not found")
+
+	_, err = CSVReader{}.Read(strings.NewReader("Band,Rating\nToto,5\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no title or track URI column")
+}
+
+func TestCSVWriter_RoundTrip(t *testing.T) {
+	addedAt := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	playlist := &Playlist{
+		Name: "Mix",
+		Tracks: []Track{
+			{
+				Artist: "Daft Punk", Artists: []string{"Daft Punk", "Romanthony"}, Title: "One More Time",
+				Album: "Discovery", Year: 2000, URI: "spotify:track:0DiWol3AO6WpXZgp0goxAV",
+				Duration: 5*time.Minute + 20*time.Second, ISRC: "GBDUW0000053", Popularity: 78, AddedAt: addedAt,
+			},
+			{Artist: "Toto", Title: "Africa"},
+			{Artist: "Crosby, Stills, Nash & Young", Title: "Ohio"},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, CSVWriter{}.Write(&buf, playlist))
+
+	expected := `ID,Title,Artists,Album,Year,Duration,ISRC,Popularity,Added At
+0DiWol3AO6WpXZgp0goxAV,One More Time,Daft Punk; Romanthony,Discovery,2000,5:20,GBDUW0000053,78,2024-05-01T12:30:00Z
+,Africa,Toto,,,,,,
+,Ohio,"Crosby, Stills, Nash & Young",,,,,,
+`
+	assert.Equal(t, expected, buf.String())
+
+	read, err := CSVReader{}.Read(&buf)
+	require.NoError(t, err)
+	assert.Empty(t, read.Warnings)
+	require.Len(t, read.Tracks, 3)
+	assert.Equal(t, "Daft Punk", read.Tracks[0].Artist)
+	assert.Equal(t, []string{"Daft Punk", "Romanthony"}, read.Tracks[0].Artists)
+	assert.Equal(t, "spotify:track:0DiWol3AO6WpXZgp0goxAV", read.Tracks[0].URI)
+	assert.Equal(t, 2000, read.Tracks[0].Year)
+	assert.Equal(t, "Toto", read.Tracks[1].Artist)
+	assert.Equal(t, "Crosby, Stills, Nash & Young", read.Tracks[2].Artist)
+	assert.Equal(t, []string{"Crosby, Stills, Nash & Young"}, read.Tracks[2].Artists)
+}
+
+func TestFormatTrackDuration(t *testing.T) {
+	assert.Equal(t, "4:05", formatTrackDuration(4*time.Minute+5*time.Second))
+	assert.Equal(t, "1:02:10", formatTrackDuration(time.Hour+2*time.Minute+10*time.Second))
+}
+
+func TestLoad_ColumnsOnlyForCSV(t *testing.T) {
+	path := createTempFile(t, "songs.txt", "Toto - Africa")
+
+	_, err := Load(path, Options{Columns: map[string]string{"title": "Song"}})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "column mappings only apply to csv and tsv")
+}
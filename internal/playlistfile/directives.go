@@ -0,0 +1,80 @@
+package playlistfile
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"auto-spotify/internal/spotifyid"
+)
+
+// Header directives are comments like "#name: My Mix" at the top of a text
+// playlist that carry the playlist's details
+const (
+	directiveName        = "name"
+	directiveDescription = "description"
+	directivePublic      = "public"
+	directiveSourceID    = "source-id"
+)
+
+// directivePattern matches "#key: value" without a space after the #, so
+// ordinary comments are never read as directives
+var directivePattern = regexp.MustCompile(`^#([A-Za-z][A-Za-z-]*):\s*(.*)$`)
+
+// parseDirective splits a directive line into its lowercase key and value
+func parseDirective(line string) (key, value string, ok bool) {
+	matches := directivePattern.FindStringSubmatch(line)
+	if matches == nil {
+		return "", "", false
+	}
+
+	key = strings.ToLower(matches[1])
+	switch key {
+	case directiveName, directiveDescription, directivePublic, directiveSourceID:
+		return key, strings.TrimSpace(matches[2]), true
+	}
+	return "", "", false
+}
+
+// applyDirective sets the playlist detail named by key, returning a warning
+// when the value is invalid
+func applyDirective(playlist *Playlist, key, value string) string {
+	switch key {
+	case directiveName:
+		playlist.Name = value
+	case directiveDescription:
+		playlist.Description = value
+	case directivePublic:
+		public, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Sprintf("#public: must be true or false, got '%s'", value)
+		}
+		playlist.Public = &public
+	case directiveSourceID:
+		id, err := spotifyid.Parse("playlist", value)
+		if err != nil {
+			return fmt.Sprintf("#source-id: '%s' is not a Spotify playlist ID, URI or URL", value)
+		}
+		playlist.SourceID = id
+	}
+	return ""
+}
+
+// writeDirectives writes the header directives for the playlist's details
+func writeDirectives(w io.Writer, playlist *Playlist) {
+	// Directives are one line each
+	oneLine := strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+	fmt.Fprintf(w, "#%s: %s\n", directiveName, oneLine.Replace(playlist.Name))
+	if playlist.Description != "" {
+		fmt.Fprintf(w, "#%s: %s\n", directiveDescription, oneLine.Replace(playlist.Description))
+	}
+	if playlist.Public != nil {
+		fmt.Fprintf(w, "#%s: %t\n", directivePublic, *playlist.Public)
+	}
+	if playlist.SourceID != "" {
+		fmt.Fprintf(w, "#%s: %s\n", directiveSourceID, playlist.SourceID)
+	}
+}
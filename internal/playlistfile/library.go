@@ -0,0 +1,328 @@
+package playlistfile
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"auto-spotify/internal/songkey"
+	"auto-spotify/internal/tags"
+)
+
+// audioExtensions are the file types indexed in a music library
+var audioExtensions = map[string]bool{
+	".mp3": true, ".flac": true, ".ogg": true, ".oga": true, ".opus": true,
+	".m4a": true, ".aac": true, ".wav": true, ".aif": true, ".aiff": true, ".wma": true,
+}
+
+var (
+	// trackNumberPattern matches leading track numbers like "01 ", "1. ", "03_" or "1-02 - "
+	trackNumberPattern = regexp.MustCompile(`^(\d{1,2}-)?\d{1,3}(\s*[-._]\s*|\s+)`)
+)
+
+// Library is a folder of music files that playlist tracks can point to. Files
+// are matched by the artist and title in their tags, or in their file name
+// like "Artist - Title.mp3", or by a title file name inside an artist folder
+// like "Artist/Album/01 Title.flac".
+type Library struct {
+	Root     string
+	Files    []LibraryFile
+	Warnings []Warning // Files whose tags couldn't be read
+
+	index  map[string]string   // songkey.Key(artist, title) to path
+	titles map[string][]string // songkey.Key("", title) to paths
+}
+
+// LibraryFile is a music file in a Library with its song details, taken from
+// its tags where it has them and from its path otherwise
+type LibraryFile struct {
+	Path   string
+	Dir    string // Folder relative to the library root, slash-separated ("" for the root)
+	Artist string
+	Title  string
+	Album  string
+	Year   int
+	Genres []string
+}
+
+// OpenLibrary indexes the music files under root
+func OpenLibrary(root string) (*Library, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid music library root '%s': %w", root, err)
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open music library: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("music library root '%s' is not a directory", root)
+	}
+
+	lib := &Library{
+		Root:   abs,
+		index:  make(map[string]string),
+		titles: make(map[string][]string),
+	}
+	err = filepath.WalkDir(abs, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !audioExtensions[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+		lib.add(path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read music library: %w", err)
+	}
+
+	return lib, nil
+}
+
+// add indexes the file at path under the artists it could belong to
+func (l *Library) add(path string) {
+	file := LibraryFile{Path: path}
+	if rel, err := filepath.Rel(l.Root, filepath.Dir(path)); err == nil && rel != "." {
+		file.Dir = filepath.ToSlash(rel)
+	}
+
+	var albumArtist string
+	songTags, err := tags.ReadFile(path)
+	switch {
+	case err == nil:
+		file.Artist, file.Title, file.Album = songTags.Artist, songTags.Title, songTags.Album
+		file.Year, file.Genres = songTags.Year, songTags.Genres
+		albumArtist = songTags.AlbumArtist
+	case !errors.Is(err, tags.ErrUnsupported):
+		l.Warnings = append(l.Warnings, Warning{Message: err.Error()})
+	}
+
+	nameArtist, nameTitle := songFromFileName(path)
+	if file.Title == "" {
+		file.Title = nameTitle
+	}
+	if file.Artist == "" {
+		file.Artist = nameArtist
+	}
+	if file.Title == "" {
+		return
+	}
+
+	artists := []string{file.Artist, albumArtist}
+	if file.Artist == "" && file.Dir != "" {
+		// Artist/Title.mp3 or Artist/Album/Title.mp3
+		dirs := strings.Split(file.Dir, "/")
+		artists = []string{dirs[len(dirs)-1]}
+		if len(dirs) > 1 {
+			artists = []string{dirs[len(dirs)-2], dirs[len(dirs)-1]}
+		}
+		file.Artist = artists[0]
+	}
+
+	for _, candidate := range artists {
+		key := songkey.Key(candidate, file.Title)
+		if _, ok := l.index[key]; !ok {
+			l.index[key] = path
+		}
+	}
+	titleKey := songkey.Key("", file.Title)
+	l.titles[titleKey] = append(l.titles[titleKey], path)
+	l.Files = append(l.Files, file)
+}
+
+// Len returns the number of indexed files
+func (l *Library) Len() int {
+	return len(l.Files)
+}
+
+// LibraryFilter picks files from a library. Empty fields match any file.
+type LibraryFilter struct {
+	Genres   []string // Any of these genres, matched by whole words ("rock" matches "Hard Rock")
+	FromYear int
+	ToYear   int
+	Folders  []string // Folders relative to the library root, including their subfolders
+}
+
+// Matches reports whether the file passes the filter
+func (f LibraryFilter) Matches(file LibraryFile) bool {
+	if len(f.Genres) > 0 && !matchesGenre(file.Genres, f.Genres) {
+		return false
+	}
+	if (f.FromYear > 0 || f.ToYear > 0) && file.Year == 0 {
+		return false
+	}
+	if f.FromYear > 0 && file.Year < f.FromYear {
+		return false
+	}
+	if f.ToYear > 0 && file.Year > f.ToYear {
+		return false
+	}
+	if len(f.Folders) > 0 && !inFolders(file.Dir, f.Folders) {
+		return false
+	}
+	return true
+}
+
+// String describes the filter, like "genre=rock year=1980-1989"
+func (f LibraryFilter) String() string {
+	var parts []string
+	for _, genre := range f.Genres {
+		parts = append(parts, "genre="+genre)
+	}
+	switch {
+	case f.FromYear > 0 && f.FromYear == f.ToYear:
+		parts = append(parts, fmt.Sprintf("year=%d", f.FromYear))
+	case f.FromYear > 0 && f.ToYear > 0:
+		parts = append(parts, fmt.Sprintf("year=%d-%d", f.FromYear, f.ToYear))
+	case f.FromYear > 0:
+		parts = append(parts, fmt.Sprintf("year=%d-", f.FromYear))
+	case f.ToYear > 0:
+		parts = append(parts, fmt.Sprintf("year=-%d", f.ToYear))
+	}
+	for _, folder := range f.Folders {
+		parts = append(parts, "folder="+cleanFolder(folder))
+	}
+	return strings.Join(parts, " ")
+}
+
+// matchesGenre reports whether any of the genres contains any of the wanted
+// genres as whole words
+func matchesGenre(genres, wanted []string) bool {
+	words := func(s string) string {
+		return " " + strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}), " ") + " "
+	}
+	for _, genre := range genres {
+		for _, want := range wanted {
+			if w := words(want); w != "  " && strings.Contains(words(genre), w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inFolders reports whether dir is one of the folders or inside one
+func inFolders(dir string, folders []string) bool {
+	dir = strings.ToLower(dir)
+	for _, folder := range folders {
+		folder = strings.ToLower(cleanFolder(folder))
+		if folder == "" || dir == folder || strings.HasPrefix(dir, folder+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanFolder normalizes a folder given relative to the library root
+func cleanFolder(folder string) string {
+	folder = filepath.ToSlash(filepath.Clean(strings.ReplaceAll(folder, "\\", "/")))
+	folder = strings.Trim(folder, "/")
+	if folder == "." {
+		return ""
+	}
+	return folder
+}
+
+// Playlist returns the files passing the filter as a playlist, in the order
+// of their paths
+func (l *Library) Playlist(filter LibraryFilter) *Playlist {
+	playlist := &Playlist{Warnings: l.Warnings}
+	for _, file := range l.Files {
+		if !filter.Matches(file) {
+			continue
+		}
+		rel, err := filepath.Rel(l.Root, file.Path)
+		if err != nil {
+			rel = file.Path
+		}
+		playlist.Tracks = append(playlist.Tracks, Track{
+			Artist: file.Artist,
+			Title:  file.Title,
+			Album:  file.Album,
+			Year:   file.Year,
+			Reason: "From library: " + filepath.ToSlash(rel),
+		})
+	}
+	return playlist
+}
+
+// Find returns the file for the track. Tracks are matched by artist and
+// title. A track without an artist is matched by title alone when a single
+// file has it.
+func (l *Library) Find(track Track) (string, bool) {
+	if l == nil || track.Title == "" {
+		return "", false
+	}
+
+	if track.Artist != "" || len(track.Artists) > 0 {
+		artists := append([]string{track.Artist}, track.Artists...)
+		for _, artist := range artists {
+			if path, ok := l.index[songkey.Key(artist, track.Title)]; ok {
+				return path, true
+			}
+		}
+		return "", false
+	}
+
+	if paths := l.titles[songkey.Key("", track.Title)]; len(paths) == 1 {
+		return paths[0], true
+	}
+	return "", false
+}
+
+// songFromFileName reads the artist and title from a file name like
+// "01 - Artist - Title.mp3". The artist is empty when the name only has a
+// title.
+func songFromFileName(path string) (artist, title string) {
+	name := filepath.Base(strings.ReplaceAll(path, "\\", "/"))
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	name = strings.ReplaceAll(name, "_", " ")
+	if stripped := trackNumberPattern.ReplaceAllString(name, ""); stripped != "" {
+		name = stripped
+	}
+
+	artist, title, _ = parseSongLine(strings.TrimSpace(name))
+	return artist, title
+}
+
+// locationPath returns the file path of a playlist location, which is a path
+// or a file:// URL. Other URLs have no path.
+func locationPath(location string) (string, bool) {
+	if !strings.Contains(location, "://") {
+		return location, true
+	}
+	u, err := url.Parse(location)
+	if err != nil || u.Scheme != "file" {
+		return "", false
+	}
+	path := u.Path
+	// file:///C:/Music/song.mp3 is the Windows path C:/Music/song.mp3
+	if len(path) >= 3 && path[0] == '/' && path[2] == ':' {
+		path = path[1:]
+	}
+	return filepath.FromSlash(path), true
+}
+
+// fileURL turns a file path into a file:// URL
+func fileURL(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+// spotifyTrackURL returns the open.spotify.com URL of a track URI
+func spotifyTrackURL(uri string) string {
+	return "https://open.spotify.com/track/" + strings.TrimPrefix(uri, trackURIPrefix)
+}
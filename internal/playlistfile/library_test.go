@@ -0,0 +1,212 @@
+package playlistfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLibrary_Find(t *testing.T) {
+	root := t.TempDir()
+	createLibraryFile(t, root, "Toto/Toto IV/06 Africa.mp3")
+	createLibraryFile(t, root, "Queen/Bohemian Rhapsody.flac")
+	createLibraryFile(t, root, "Singles/03 - Daft Punk - One More Time.m4a")
+	createLibraryFile(t, root, "Misc/Intro.ogg")
+	createLibraryFile(t, root, "Other/Intro.ogg")
+	createLibraryFile(t, root, "Toto/cover.jpg")
+
+	lib, err := OpenLibrary(root)
+	require.NoError(t, err)
+	assert.Equal(t, 5, lib.Len())
+
+	tests := []struct {
+		name  string
+		track Track
+		want  string
+	}{
+		{"artist folder", Track{Artist: "toto", Title: "Africa"}, "Toto/Toto IV/06 Africa.mp3"},
+		{"version suffix", Track{Artist: "Queen", Title: "Bohemian Rhapsody - Remastered 2011"}, "Queen/Bohemian Rhapsody.flac"},
+		{"artist in file name", Track{Artist: "Daft Punk", Title: "One More Time"}, "Singles/03 - Daft Punk - One More Time.m4a"},
+		{"featured artist", Track{Artist: "Someone", Artists: []string{"Someone", "Toto"}, Title: "Africa"}, "Toto/Toto IV/06 Africa.mp3"},
+		{"title only", Track{Title: "Africa"}, "Toto/Toto IV/06 Africa.mp3"},
+		{"other artist", Track{Artist: "Weezer", Title: "Africa"}, ""},
+		{"ambiguous title", Track{Title: "Intro"}, ""},
+		{"missing", Track{Artist: "Toto", Title: "Rosanna"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, ok := lib.Find(tt.track)
+			if tt.want == "" {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, filepath.Join(root, filepath.FromSlash(tt.want)), path)
+		})
+	}
+}
+
+func TestLibrary_FindNil(t *testing.T) {
+	var lib *Library
+	_, ok := lib.Find(Track{Artist: "Toto", Title: "Africa"})
+	assert.False(t, ok)
+}
+
+func TestOpenLibrary_Errors(t *testing.T) {
+	_, err := OpenLibrary(filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to open music library")
+
+	file := createTempFile(t, "song.mp3", "")
+	_, err = OpenLibrary(file)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not a directory")
+}
+
+func TestSongFromFileName(t *testing.T) {
+	tests := []struct {
+		path   string
+		artist string
+		title  string
+	}{
+		{"/music/Queen - Bohemian Rhapsody.mp3", "Queen", "Bohemian Rhapsody"},
+		{"/music/01 Africa.mp3", "", "Africa"},
+		{"/music/1-02 - Toto - Rosanna.flac", "Toto", "Rosanna"},
+		{"/music/07_Hold_the_Line.ogg", "", "Hold the Line"},
+		{`C:\Music\03. Toto - Africa.mp3`, "Toto", "Africa"},
+		{"/music/1999.mp3", "", "1999"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			artist, title := songFromFileName(tt.path)
+			assert.Equal(t, tt.artist, artist)
+			assert.Equal(t, tt.title, title)
+		})
+	}
+}
+
+func TestLocationPath(t *testing.T) {
+	path, ok := locationPath("file:///music/Toto/Africa%20Live.mp3")
+	require.True(t, ok)
+	assert.Equal(t, filepath.FromSlash("/music/Toto/Africa Live.mp3"), path)
+
+	path, ok = locationPath("file:///C:/Music/Africa.mp3")
+	require.True(t, ok)
+	assert.Equal(t, filepath.FromSlash("C:/Music/Africa.mp3"), path)
+
+	_, ok = locationPath("https://example.com/stream")
+	assert.False(t, ok)
+
+	assert.Equal(t, "file:///music/Toto/Africa%20Live.mp3", fileURL("/music/Toto/Africa Live.mp3"))
+}
+
+func TestOpenLibrary_Tags(t *testing.T) {
+	root := t.TempDir()
+	createTaggedFile(t, root, "Unsorted/track01.mp3", "Africa", "Toto", "Toto IV", "1982", 17)
+	createTaggedFile(t, root, "Unsorted/track02.mp3", "", "", "", "", 255)
+	createLibraryFile(t, root, "Unsorted/Daft Punk - One More Time.mp3")
+
+	lib, err := OpenLibrary(root)
+	require.NoError(t, err)
+	require.Len(t, lib.Files, 3)
+
+	assert.Equal(t, LibraryFile{
+		Path:   filepath.Join(root, "Unsorted", "track01.mp3"),
+		Dir:    "Unsorted",
+		Artist: "Toto",
+		Title:  "Africa",
+		Album:  "Toto IV",
+		Year:   1982,
+		Genres: []string{"Rock"},
+	}, lib.Files[1])
+
+	path, ok := lib.Find(Track{Artist: "Toto", Title: "Africa"})
+	require.True(t, ok)
+	assert.Equal(t, lib.Files[1].Path, path)
+
+	// Untagged files fall back to their name
+	assert.Equal(t, "Daft Punk", lib.Files[0].Artist)
+	assert.Equal(t, "One More Time", lib.Files[0].Title)
+	assert.Equal(t, "Unsorted", lib.Files[2].Artist)
+	assert.Equal(t, "track02", lib.Files[2].Title)
+}
+
+func TestLibraryFilter(t *testing.T) {
+	file := LibraryFile{Dir: "Rock/80s", Year: 1982, Genres: []string{"Hard Rock", "Hip-Hop"}}
+
+	tests := []struct {
+		name   string
+		filter LibraryFilter
+		want   bool
+	}{
+		{"empty", LibraryFilter{}, true},
+		{"genre word", LibraryFilter{Genres: []string{"rock"}}, true},
+		{"genre punctuation", LibraryFilter{Genres: []string{"hip hop"}}, true},
+		{"genre part of word", LibraryFilter{Genres: []string{"hard roc"}}, false},
+		{"any genre", LibraryFilter{Genres: []string{"jazz", "ROCK"}}, true},
+		{"year range", LibraryFilter{FromYear: 1980, ToYear: 1989}, true},
+		{"year before", LibraryFilter{ToYear: 1979}, false},
+		{"year after", LibraryFilter{FromYear: 1983}, false},
+		{"folder", LibraryFilter{Folders: []string{"rock"}}, true},
+		{"subfolder", LibraryFilter{Folders: []string{"Rock/80s/"}}, true},
+		{"folder prefix", LibraryFilter{Folders: []string{"Ro"}}, false},
+		{"all", LibraryFilter{Genres: []string{"rock"}, FromYear: 1982, ToYear: 1982, Folders: []string{"Jazz", "Rock"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Matches(file))
+		})
+	}
+
+	assert.False(t, LibraryFilter{FromYear: 1980}.Matches(LibraryFile{}), "files without a year don't match a year filter")
+}
+
+func TestLibraryFilter_String(t *testing.T) {
+	assert.Equal(t, "", LibraryFilter{}.String())
+	assert.Equal(t, "genre=rock year=1980-1989 folder=Rock/80s", LibraryFilter{Genres: []string{"rock"}, FromYear: 1980, ToYear: 1989, Folders: []string{"./Rock/80s/"}}.String())
+	assert.Equal(t, "year=1982", LibraryFilter{FromYear: 1982, ToYear: 1982}.String())
+	assert.Equal(t, "year=-1979", LibraryFilter{ToYear: 1979}.String())
+}
+
+func TestLibrary_Playlist(t *testing.T) {
+	root := t.TempDir()
+	createTaggedFile(t, root, "Rock/africa.mp3", "Africa", "Toto", "Toto IV", "1982", 17)
+	createTaggedFile(t, root, "Jazz/take-five.mp3", "Take Five", "The Dave Brubeck Quartet", "Time Out", "1959", 8)
+
+	lib, err := OpenLibrary(root)
+	require.NoError(t, err)
+
+	playlist := lib.Playlist(LibraryFilter{Genres: []string{"rock"}})
+
+	require.Len(t, playlist.Tracks, 1)
+	assert.Equal(t, Track{Artist: "Toto", Title: "Africa", Album: "Toto IV", Year: 1982, Reason: "From library: Rock/africa.mp3"}, playlist.Tracks[0])
+	assert.Len(t, lib.Playlist(LibraryFilter{}).Tracks, 2)
+}
+
+// createTaggedFile creates a file under root with an ID3v1 tag
+func createTaggedFile(t *testing.T, root, rel, title, artist, album, year string, genre byte) {
+	tag := make([]byte, 128)
+	copy(tag, "TAG")
+	copy(tag[3:33], title)
+	copy(tag[33:63], artist)
+	copy(tag[63:93], album)
+	copy(tag[93:97], year)
+	tag[127] = genre
+
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, append(make([]byte, 64), tag...), 0644))
+}
+
+// createLibraryFile creates an empty file at the slash-separated path under root
+func createLibraryFile(t *testing.T, root, rel string) {
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, nil, 0644))
+}
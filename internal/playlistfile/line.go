@@ -0,0 +1,212 @@
+package playlistfile
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"auto-spotify/internal/spotifyid"
+)
+
+// A song line has the form
+//
+//	Artist - Title [Album, 1986] spotify:track:ID # comment
+//
+// where everything but the song is optional, and a line with only a track
+// URI or URL is fine too.
+
+var yearPattern = regexp.MustCompile(`^\d{4}$`)
+
+const trackURIPrefix = "spotify:track:"
+
+// literalMask marks the runes that are quoted or escaped, including the
+// quotes and backslashes themselves, so they are never read as syntax
+func literalMask(runes []rune) []bool {
+	mask := make([]bool, len(runes))
+	quotes := strings.Count(unescaped(runes), `"`)%2 == 0
+	inQuote := false
+	for i := 0; i < len(runes); i++ {
+		switch {
+		case runes[i] == '\\' && i+1 < len(runes):
+			mask[i], mask[i+1] = true, true
+			i++
+		case runes[i] == '"' && quotes:
+			mask[i] = true
+			inQuote = !inQuote
+		default:
+			mask[i] = inQuote
+		}
+	}
+	return mask
+}
+
+// unquote resolves the quotes and escapes in s
+func unquote(s string) string {
+	tokens, _ := tokenizeLine(s)
+	return joinTokens(tokens)
+}
+
+// parseLine reads a song line into a track, returning a warning when the
+// line could be read in more than one way
+func parseLine(line string) (Track, string) {
+	var track Track
+	runes := []rune(line)
+	mask := literalMask(runes)
+
+	// A comment starts at a # or // with whitespace before it
+	for i := 1; i < len(runes); i++ {
+		if mask[i] || !unicode.IsSpace(runes[i-1]) {
+			continue
+		}
+		if runes[i] == '#' && (i+1 == len(runes) || unicode.IsSpace(runes[i+1])) ||
+			strings.HasPrefix(string(runes[i:]), "//") {
+			track.Comment = strings.TrimSpace(strings.TrimLeft(string(runes[i:]), "#/"))
+			runes, mask = runes[:i], mask[:i]
+			break
+		}
+	}
+	runes, mask = trimRunes(runes, mask)
+
+	// A track URI or URL is the last word
+	if start := lastField(runes, mask); start < len(runes) {
+		if uri, ok := trackURI(string(runes[start:])); ok {
+			track.URI = uri
+			runes, mask = trimRunes(runes[:start], mask[:start])
+		}
+	}
+
+	// Album and year go in brackets after the song
+	if n := len(runes); n > 0 && runes[n-1] == ']' && !mask[n-1] {
+		for i := n - 2; i >= 0; i-- {
+			if runes[i] != '[' || mask[i] {
+				continue
+			}
+			if i == 0 || unicode.IsSpace(runes[i-1]) {
+				track.Album, track.Year = parseAlbum(runes[i+1:n-1], mask[i+1:n-1])
+				runes, mask = trimRunes(runes[:i], mask[:i])
+			}
+			break
+		}
+	}
+
+	if len(runes) == 0 {
+		if track.URI == "" && track.Album != "" {
+			return track, "only an album was given, add the artist and title"
+		}
+		return track, ""
+	}
+
+	artist, title, warning := parseSongLine(string(runes))
+	track.Artist, track.Title = artist, title
+	return track, warning
+}
+
+// parseAlbum reads "Album, 1986", "Album" or "1986"
+func parseAlbum(runes []rune, mask []bool) (string, int) {
+	content := strings.TrimSpace(string(runes))
+	if yearPattern.MatchString(content) {
+		year, _ := strconv.Atoi(content)
+		return "", year
+	}
+
+	for i := len(runes) - 1; i > 0; i-- {
+		if runes[i] != ',' || mask[i] {
+			continue
+		}
+		if year := strings.TrimSpace(string(runes[i+1:])); yearPattern.MatchString(year) {
+			y, _ := strconv.Atoi(year)
+			return unquote(string(runes[:i])), y
+		}
+		break
+	}
+
+	return unquote(content), 0
+}
+
+// trimRunes trims unquoted whitespace from both ends
+func trimRunes(runes []rune, mask []bool) ([]rune, []bool) {
+	for len(runes) > 0 && !mask[len(runes)-1] && unicode.IsSpace(runes[len(runes)-1]) {
+		runes, mask = runes[:len(runes)-1], mask[:len(mask)-1]
+	}
+	for len(runes) > 0 && !mask[0] && unicode.IsSpace(runes[0]) {
+		runes, mask = runes[1:], mask[1:]
+	}
+	return runes, mask
+}
+
+// lastField returns where the last unquoted whitespace-separated word starts
+func lastField(runes []rune, mask []bool) int {
+	for i := len(runes) - 1; i >= 0; i-- {
+		if mask[i] {
+			return len(runes)
+		}
+		if unicode.IsSpace(runes[i]) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// trackURI turns a Spotify track URI or open.spotify.com URL into a track URI
+func trackURI(s string) (string, bool) {
+	if !spotifyid.IsLink(s) {
+		return "", false
+	}
+	id, err := spotifyid.Parse("track", s)
+	if err != nil {
+		return "", false
+	}
+	return trackURIPrefix + id, true
+}
+
+// formatLine writes a track as a song line that parseLine reads back as the
+// same track
+func formatLine(track Track) string {
+	var parts []string
+	if track.Title != "" {
+		if track.Artist != "" {
+			parts = append(parts, formatSongLine(track.Artist, track.Title))
+		} else {
+			parts = append(parts, quoteTitle(track.Title))
+		}
+	}
+
+	switch {
+	case track.Album != "" && track.Year != 0:
+		parts = append(parts, fmt.Sprintf("[%s, %d]", quoteAlbum(track.Album), track.Year))
+	case track.Album != "":
+		parts = append(parts, fmt.Sprintf("[%s]", quoteAlbum(track.Album)))
+	case track.Year != 0:
+		parts = append(parts, fmt.Sprintf("[%d]", track.Year))
+	}
+
+	if track.URI != "" {
+		parts = append(parts, track.URI)
+	}
+	if track.Comment != "" {
+		parts = append(parts, "# "+track.Comment)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// quoteTitle quotes a title written without an artist if it would otherwise
+// be split into artist and title, or read with a warning
+func quoteTitle(title string) string {
+	quoted := quoteField(title)
+	if artist, _, warning := parseSongLine(quoted); artist != "" || warning != "" {
+		return quote(title)
+	}
+	return quoted
+}
+
+// quoteAlbum quotes album names that would otherwise be read as a year or
+// split at a comma
+func quoteAlbum(album string) string {
+	if yearPattern.MatchString(album) || strings.Contains(album, ",") {
+		return quote(album)
+	}
+	return quoteField(album)
+}
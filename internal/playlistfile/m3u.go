@@ -0,0 +1,210 @@
+package playlistfile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register(Format{
+		Name:       "m3u8",
+		Extensions: []string{".m3u8", ".m3u"},
+		Reader:     M3UReader{},
+		Writer:     M3UWriter{},
+		Locations:  true,
+	})
+}
+
+// Extended M3U directives
+const (
+	m3uHeader   = "#EXTM3U"
+	m3uPlaylist = "#PLAYLIST:"
+	m3uInfo     = "#EXTINF:"
+	m3uAlbum    = "#EXTALB:"
+	m3uArtist   = "#EXTART:"
+)
+
+// M3UReader reads M3U and M3U8 playlists from media players. Songs come from
+// the "#EXTINF:seconds,Artist - Title" line before each location, or from
+// the location's file name when there is none. Locations that are Spotify
+// track URLs or URIs are used as is.
+type M3UReader struct{}
+
+// Read parses the entries in r
+func (M3UReader) Read(r io.Reader) (*Playlist, error) {
+	playlist := &Playlist{}
+	scanner := bufio.NewScanner(r)
+
+	// Details of the entry whose location comes next
+	var (
+		pending   Track
+		entryLine int
+	)
+
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if lineNum == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+
+		switch {
+		case line == "" || line == m3uHeader:
+			continue
+		case strings.HasPrefix(line, m3uPlaylist):
+			playlist.Name = strings.TrimSpace(strings.TrimPrefix(line, m3uPlaylist))
+			continue
+		case strings.HasPrefix(line, m3uInfo):
+			pending = Track{}
+			entryLine = lineNum
+			duration, display := parseExtInf(strings.TrimPrefix(line, m3uInfo))
+			pending.Duration = duration
+			artist, title, warning := parseSongLine(display)
+			pending.Artist, pending.Title = artist, title
+			if warning != "" {
+				playlist.Warnings = append(playlist.Warnings, Warning{Line: lineNum, Message: warning})
+			}
+			continue
+		case strings.HasPrefix(line, m3uAlbum):
+			pending.Album = strings.TrimSpace(strings.TrimPrefix(line, m3uAlbum))
+			continue
+		case strings.HasPrefix(line, m3uArtist):
+			if artist := strings.TrimSpace(strings.TrimPrefix(line, m3uArtist)); artist != "" && pending.Artist == "" {
+				pending.Artist = artist
+			}
+			continue
+		case strings.HasPrefix(line, "#"):
+			// Comments and directives of other players
+			continue
+		}
+
+		track := pending
+		if entryLine == 0 {
+			entryLine = lineNum
+		}
+		track.Line = entryLine
+		pending, entryLine = Track{}, 0
+
+		if uri, ok := trackURI(line); ok {
+			track.URI = uri
+		} else if track.Title == "" {
+			if path, ok := locationPath(line); ok {
+				track.Artist, track.Title = songFromFileName(path)
+			}
+		}
+
+		if track.Title == "" && track.URI == "" {
+			playlist.Warnings = append(playlist.Warnings, Warning{Line: track.Line, Message: fmt.Sprintf("no song found for '%s', skipping it", line)})
+			continue
+		}
+		playlist.Tracks = append(playlist.Tracks, track)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return playlist, nil
+}
+
+// parseExtInf splits the value of an #EXTINF line, like
+// `354 tvg-id="x",Queen - Bohemian Rhapsody`, into duration and display title.
+// The duration is zero when unknown.
+func parseExtInf(value string) (time.Duration, string) {
+	// The display title starts after the first comma outside quoted attributes
+	inQuote := false
+	split := -1
+	for i, r := range value {
+		if r == '"' {
+			inQuote = !inQuote
+		} else if r == ',' && !inQuote {
+			split = i
+			break
+		}
+	}
+
+	info, display := value, ""
+	if split >= 0 {
+		info, display = value[:split], value[split+1:]
+	}
+
+	var duration time.Duration
+	if fields := strings.Fields(info); len(fields) > 0 {
+		if seconds, err := strconv.ParseFloat(fields[0], 64); err == nil && seconds > 0 {
+			duration = time.Duration(seconds * float64(time.Second))
+		}
+	}
+	return duration, strings.TrimSpace(display)
+}
+
+// M3UWriter writes extended M3U playlists in UTF-8. Each track points to its
+// file in the Library when it has one there, otherwise to its Spotify URL.
+type M3UWriter struct {
+	Library *Library
+}
+
+func (m M3UWriter) withOptions(opts Options) (Writer, error) {
+	m.Library = opts.Library
+	return m, nil
+}
+
+// Write writes the playlist to w
+func (m M3UWriter) Write(w io.Writer, playlist *Playlist) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, m3uHeader)
+	if playlist.Name != "" {
+		fmt.Fprintf(bw, "%s%s\n", m3uPlaylist, oneLine(playlist.Name))
+	}
+
+	for _, track := range playlist.Tracks {
+		display := track.Title
+		if track.Artist != "" {
+			display = track.Artist + " - " + track.Title
+		}
+
+		location := trackLocation(m.Library, track, false)
+		if location == "" {
+			fmt.Fprintf(bw, "# no location for %s\n", oneLine(display))
+			continue
+		}
+
+		seconds := -1
+		if track.Duration > 0 {
+			seconds = int(track.Duration.Round(time.Second) / time.Second)
+		}
+		fmt.Fprintf(bw, "%s%d,%s\n", m3uInfo, seconds, oneLine(display))
+		if track.Album != "" {
+			fmt.Fprintf(bw, "%s%s\n", m3uAlbum, oneLine(track.Album))
+		}
+		fmt.Fprintln(bw, location)
+	}
+
+	return bw.Flush()
+}
+
+// trackLocation returns where a playlist entry points to: the track's file
+// in the library, or its Spotify URL. Files are written as file:// URLs when
+// asURL is set. It returns "" when the track has neither.
+func trackLocation(lib *Library, track Track, asURL bool) string {
+	if path, ok := lib.Find(track); ok {
+		if asURL {
+			return fileURL(path)
+		}
+		return path
+	}
+	if track.URI != "" {
+		return spotifyTrackURL(track.URI)
+	}
+	return ""
+}
+
+// oneLine replaces line breaks so a value can't start a new entry
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,119 @@
+package playlistfile
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestM3UReader(t *testing.T) {
+	content := "\ufeff#EXTM3U\n" +
+		"#PLAYLIST:Road Trip\n" +
+		"#EXTINF:296,Toto - Africa\n" +
+		"#EXTALB:Toto IV\n" +
+		"/music/Toto/Toto IV/06 Africa.mp3\n" +
+		"\n" +
+		`#EXTINF:-1 tvg-name="a,b",Daft Punk - One More Time` + "\n" +
+		"https://open.spotify.com/track/0DiWol3AO6WpXZgp0goxAV\n" +
+		"# a comment\n" +
+		"/music/Queen/03 - Queen - Bohemian Rhapsody.flac\n" +
+		"spotify:track:2374M0fQpWi3dLnB54qaLX\n" +
+		"http://radio.example.com/stream\n"
+
+	playlist, err := M3UReader{}.Read(strings.NewReader(content))
+
+	require.NoError(t, err)
+	assert.Equal(t, "Road Trip", playlist.Name)
+	require.Len(t, playlist.Tracks, 4)
+	assert.Equal(t, Track{Artist: "Toto", Title: "Africa", Album: "Toto IV", Duration: 296 * time.Second, Line: 3}, playlist.Tracks[0])
+	assert.Equal(t, Track{Artist: "Daft Punk", Title: "One More Time", URI: "spotify:track:0DiWol3AO6WpXZgp0goxAV", Line: 7}, playlist.Tracks[1])
+	assert.Equal(t, Track{Artist: "Queen", Title: "Bohemian Rhapsody", Line: 10}, playlist.Tracks[2])
+	assert.Equal(t, Track{URI: "spotify:track:2374M0fQpWi3dLnB54qaLX", Line: 11}, playlist.Tracks[3])
+
+	require.Len(t, playlist.Warnings, 1)
+	assert.Equal(t, 12, playlist.Warnings[0].Line)
+}
+
+func TestParseExtInf(t *testing.T) {
+	tests := []struct {
+		value    string
+		duration time.Duration
+		display  string
+	}{
+		{"354,Queen - Bohemian Rhapsody", 354 * time.Second, "Queen - Bohemian Rhapsody"},
+		{"-1,Unknown Length", 0, "Unknown Length"},
+		{`0 logo="x,y",Station`, 0, "Station"},
+		{"12.5, Spaced ", 12500 * time.Millisecond, "Spaced"},
+		{"42", 42 * time.Second, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			duration, display := parseExtInf(tt.value)
+			assert.Equal(t, tt.duration, duration)
+			assert.Equal(t, tt.display, display)
+		})
+	}
+}
+
+func TestM3UWriter(t *testing.T) {
+	playlist := &Playlist{
+		Name: "Friday Mix",
+		Tracks: []Track{
+			{Artist: "Toto", Title: "Africa", Album: "Toto IV", Duration: 295600 * time.Millisecond, URI: "spotify:track:2374M0fQpWi3dLnB54qaLX"},
+			{Title: "Untitled"},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, M3UWriter{}.Write(&buf, playlist))
+
+	expected := "#EXTM3U\n" +
+		"#PLAYLIST:Friday Mix\n" +
+		"#EXTINF:296,Toto - Africa\n" +
+		"#EXTALB:Toto IV\n" +
+		"https://open.spotify.com/track/2374M0fQpWi3dLnB54qaLX\n" +
+		"# no location for Untitled\n"
+	assert.Equal(t, expected, buf.String())
+
+	read, err := M3UReader{}.Read(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, "Friday Mix", read.Name)
+	require.Len(t, read.Tracks, 1)
+	assert.Equal(t, "spotify:track:2374M0fQpWi3dLnB54qaLX", read.Tracks[0].URI)
+	assert.Equal(t, 296*time.Second, read.Tracks[0].Duration)
+}
+
+func TestM3UWriter_Library(t *testing.T) {
+	root := t.TempDir()
+	createLibraryFile(t, root, "Toto/Toto IV/06 Africa.mp3")
+	lib, err := OpenLibrary(root)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "mix.m3u8")
+	playlist := &Playlist{Tracks: []Track{
+		{Artist: "Toto", Title: "Africa - 2008 Remaster", URI: "spotify:track:2374M0fQpWi3dLnB54qaLX"},
+		{Artist: "Queen", Title: "Bohemian Rhapsody", URI: "spotify:track:4u7EnebtmKWzUH433cf5Qv"},
+	}}
+	require.NoError(t, Save(path, playlist, Options{Library: lib}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	content := string(data)
+	assert.Contains(t, content, filepath.Join(root, "Toto", "Toto IV", "06 Africa.mp3")+"\n")
+	assert.Contains(t, content, "https://open.spotify.com/track/4u7EnebtmKWzUH433cf5Qv\n")
+}
+
+func TestForPath_Locations(t *testing.T) {
+	assert.Equal(t, "m3u8", ForPath("mix.m3u").Name)
+	assert.Equal(t, "m3u8", ForPath("mix.M3U8").Name)
+	assert.Equal(t, "xspf", ForPath("mix.xspf").Name)
+	assert.True(t, ForPath("mix.xspf").Locations)
+	assert.False(t, ForPath("mix.txt").Locations)
+}
@@ -0,0 +1,380 @@
+package playlistfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Track is a song in a playlist file. Files written by export carry the
+// details of the Spotify track, files written by hand often only the artist
+// and title.
+type Track struct {
+	Artist string
+	Title  string
+	Album  string
+	Year   int
+	Reason string
+
+	URI     string // Spotify track URI, the track is used as is without searching
+	Section string // Section of the file the track is listed in
+	Comment string // Comment written after the track
+
+	// Details of exported Spotify tracks
+	Artists    []string // All artists, starting with Artist
+	Duration   time.Duration
+	ISRC       string
+	Popularity int
+	AddedAt    time.Time
+
+	Line        int  // Line the track was read from (0 when unknown)
+	Unavailable bool // Not playable in the playlist's market
+}
+
+// Playlist is the content of a playlist file
+type Playlist struct {
+	Name        string
+	Description string
+	Public      *bool  // Visibility to give the playlist (nil to leave it to the options)
+	SourceID    string // ID of the Spotify playlist the file was exported from
+	Market      string // Market the tracks were exported for
+	Tracks      []Track
+	Warnings    []Warning // Problems found while reading the file
+}
+
+// Warning points out a part of a playlist file that could be read in more
+// than one way or was skipped
+type Warning struct {
+	Line    int // 0 for formats without meaningful lines
+	Message string
+}
+
+func (w Warning) String() string {
+	if w.Line == 0 {
+		return w.Message
+	}
+	return fmt.Sprintf("line %d: %s", w.Line, w.Message)
+}
+
+// Options tune how playlist files are read and written
+type Options struct {
+	Format  string            // Format name, detected from the file extension when empty
+	Columns map[string]string // CSV and TSV: song field (artist, title, ...) to column header or 1-based index
+	Library *Library          // M3U8 and XSPF: local files to point tracks to instead of Spotify URLs
+}
+
+// Reader parses a playlist file
+type Reader interface {
+	Read(r io.Reader) (*Playlist, error)
+}
+
+// optionReader is implemented by readers that take options
+type optionReader interface {
+	withOptions(opts Options) (Reader, error)
+}
+
+// Writer writes a playlist file
+type Writer interface {
+	Write(w io.Writer, playlist *Playlist) error
+}
+
+// optionWriter is implemented by writers that take options
+type optionWriter interface {
+	withOptions(opts Options) (Writer, error)
+}
+
+// Format is a playlist file format with its codec
+type Format struct {
+	Name       string
+	Extensions []string // File extensions including the dot, the first one is used when writing
+	Reader     Reader
+	Writer     Writer
+	Locations  bool // Tracks are written with a file location, see Options.Library
+}
+
+// DefaultFormat is used for files whose extension isn't registered
+const DefaultFormat = "txt"
+
+var formats = make(map[string]Format)
+
+// Register adds a format, replacing any format with the same name
+func Register(format Format) {
+	formats[format.Name] = format
+}
+
+// Lookup returns the format with the given name
+func Lookup(name string) (Format, error) {
+	format, ok := formats[strings.ToLower(name)]
+	if !ok {
+		return Format{}, fmt.Errorf("unknown playlist format '%s' (supported: %s)", name, strings.Join(Names(), ", "))
+	}
+	return format, nil
+}
+
+// ForPath returns the format registered for the extension of path, falling
+// back to the plain text format
+func ForPath(path string) Format {
+	if format, ok := forExtension(path); ok {
+		return format
+	}
+	return formats[DefaultFormat]
+}
+
+// IsPlaylistFile reports whether a format is registered for the extension of path
+func IsPlaylistFile(path string) bool {
+	_, ok := forExtension(path)
+	return ok
+}
+
+// forExtension returns the format registered for the extension of path
+func forExtension(path string) (Format, bool) {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, name := range Names() {
+		for _, candidate := range formats[name].Extensions {
+			if candidate == ext {
+				return formats[name], true
+			}
+		}
+	}
+	return Format{}, false
+}
+
+// Names returns the names of the registered formats in alphabetical order
+func Names() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Extension returns the extension used when writing files in this format
+func (f Format) Extension() string {
+	if len(f.Extensions) == 0 {
+		return "." + f.Name
+	}
+	return f.Extensions[0]
+}
+
+// resolve picks the named format, or the format matching the path when no name is given
+func resolve(path, name string) (Format, error) {
+	if name != "" {
+		return Lookup(name)
+	}
+	return ForPath(path), nil
+}
+
+// Stdin is the path that stands for standard input in Load
+const Stdin = "-"
+
+// Load reads the playlist file at path, or standard input for Stdin. The
+// format is detected from the extension unless one is named. Playlists
+// without a name are named after the file and today's date, and tracks
+// without a reason point to the line they were read from.
+func Load(path string, opts Options) (*Playlist, error) {
+	if path == Stdin {
+		return Read(os.Stdin, opts)
+	}
+
+	codec, err := resolve(path, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := configuredReader(codec, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	playlist, err := reader.Read(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file %s: %w", path, err)
+	}
+
+	if len(playlist.Tracks) == 0 {
+		return nil, fmt.Errorf("no songs found in file %s", path)
+	}
+	complete(playlist, "file: "+path, path, NameFromPath(path))
+	return playlist, nil
+}
+
+// Read reads a playlist piped in from another program. The format is
+// detected from the content unless one is named, and playlists without a
+// name are named after today's date.
+func Read(r io.Reader, opts Options) (*Playlist, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading stdin: %w", err)
+	}
+
+	codec := Detect(data)
+	if opts.Format != "" {
+		var err error
+		if codec, err = Lookup(opts.Format); err != nil {
+			return nil, err
+		}
+	}
+	reader, err := configuredReader(codec, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	playlist, err := reader.Read(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error reading stdin: %w", err)
+	}
+
+	if len(playlist.Tracks) == 0 {
+		return nil, fmt.Errorf("no songs found in stdin")
+	}
+	complete(playlist, "stdin", "stdin", "Playlist")
+	return playlist, nil
+}
+
+// Detect guesses the format of the playlist in data from its first
+// characters, falling back to the plain text format. JSON and YAML are only
+// detected when the whole playlist parses that way, so song lines like
+// "[Intro] - Artist" stay text. CSV and TSV aren't detected.
+func Detect(data []byte) Format {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	text := strings.TrimSpace(string(data))
+
+	name := DefaultFormat
+	switch {
+	case strings.HasPrefix(text, "{") || strings.HasPrefix(text, "["):
+		if json.Valid(data) {
+			name = "json"
+		}
+	case strings.HasPrefix(text, "---") || strings.HasPrefix(text, "playlist_name:") || strings.HasPrefix(text, "songs:"):
+		var doc document
+		if yaml.Unmarshal(data, &doc) == nil {
+			name = "yaml"
+		}
+	case strings.HasPrefix(text, m3uHeader):
+		name = "m3u8"
+	case strings.HasPrefix(text, "<?xml") || strings.HasPrefix(text, "<playlist"):
+		name = "xspf"
+	}
+
+	if format, ok := formats[name]; ok {
+		return format
+	}
+	return formats[DefaultFormat]
+}
+
+// complete fills in what the playlist doesn't set itself: reasons pointing
+// tracks to the line they were read from (origin is "file: path" or
+// "stdin"), a dated name and a description mentioning where it came from
+func complete(playlist *Playlist, origin, from, name string) {
+	for i := range playlist.Tracks {
+		track := &playlist.Tracks[i]
+		if track.Reason == "" && track.Line > 0 {
+			location := origin
+			if track.Section != "" {
+				location += ", " + track.Section
+			}
+			track.Reason = fmt.Sprintf("From %s (line %d)", location, track.Line)
+		}
+	}
+
+	if playlist.Name == "" {
+		// Add a timestamp to avoid duplicates when the file doesn't name the playlist
+		playlist.Name = fmt.Sprintf("%s (%s)", name, time.Now().Format("Jan 2, 2006"))
+	}
+	if playlist.Description == "" {
+		playlist.Description = fmt.Sprintf("Playlist loaded from %s (%d songs)", from, len(playlist.Tracks))
+	}
+}
+
+// configuredReader returns the format's reader with the options applied
+func configuredReader(codec Format, opts Options) (Reader, error) {
+	if codec.Reader == nil {
+		return nil, fmt.Errorf("reading %s playlists is not supported", codec.Name)
+	}
+
+	if configurable, ok := codec.Reader.(optionReader); ok {
+		return configurable.withOptions(opts)
+	}
+	if len(opts.Columns) > 0 {
+		return nil, fmt.Errorf("column mappings only apply to csv and tsv playlists, not %s", codec.Name)
+	}
+	return codec.Reader, nil
+}
+
+// configuredWriter returns the format's writer with the options applied
+func configuredWriter(codec Format, opts Options) (Writer, error) {
+	if codec.Writer == nil {
+		return nil, fmt.Errorf("writing %s playlists is not supported", codec.Name)
+	}
+
+	if configurable, ok := codec.Writer.(optionWriter); ok {
+		return configurable.withOptions(opts)
+	}
+	return codec.Writer, nil
+}
+
+// Save writes the playlist to path in the format named in the options, or
+// the format matching the path when none is named
+func Save(path string, playlist *Playlist, opts Options) error {
+	codec, err := resolve(path, opts.Format)
+	if err != nil {
+		return err
+	}
+	writer, err := configuredWriter(codec, opts)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file '%s': %w", path, err)
+	}
+
+	if err := writer.Write(file, playlist); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write file '%s': %w", path, err)
+	}
+	return file.Close()
+}
+
+// Write writes the playlist to w, such as standard output, in the format
+// named in the options or the plain text format when none is named
+func Write(w io.Writer, playlist *Playlist, opts Options) error {
+	name := opts.Format
+	if name == "" {
+		name = DefaultFormat
+	}
+	codec, err := Lookup(name)
+	if err != nil {
+		return err
+	}
+	writer, err := configuredWriter(codec, opts)
+	if err != nil {
+		return err
+	}
+	return writer.Write(w, playlist)
+}
+
+// NameFromPath turns a file name like "metal-songs.txt" into "Metal Songs"
+func NameFromPath(path string) string {
+	// Handle Windows paths on any platform
+	name := filepath.Base(strings.ReplaceAll(path, "\\", "/"))
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	name = strings.ReplaceAll(name, "_", " ")
+	name = strings.ReplaceAll(name, "-", " ")
+	return strings.Title(name)
+}
@@ -0,0 +1,244 @@
+package playlistfile
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Success(t *testing.T) {
+	content := `# Test playlist
+Metallica - Master of Puppets
+Iron Maiden: Run to the Hills
+
+// Another comment
+AC/DC - Thunderstruck`
+
+	path := createTempFile(t, "test-playlist.txt", content)
+
+	playlist, err := Load(path, Options{})
+
+	require.NoError(t, err)
+	assert.Contains(t, playlist.Description, "test-playlist.txt")
+	assert.Contains(t, playlist.Description, "3 songs")
+	require.Len(t, playlist.Tracks, 3)
+
+	// Check reasons contain file path and line numbers
+	assert.Equal(t, "From file: "+path+" (line 2)", playlist.Tracks[0].Reason)
+	assert.Equal(t, "From file: "+path+" (line 6)", playlist.Tracks[2].Reason)
+}
+
+func TestLoad_AutoGeneratedName(t *testing.T) {
+	content := `Metallica - Master of Puppets
+Iron Maiden - Run to the Hills`
+
+	path := createTempFile(t, "metal-songs.txt", content)
+
+	playlist, err := Load(path, Options{})
+
+	require.NoError(t, err)
+	assert.Contains(t, playlist.Name, "Metal Songs")
+	assert.Contains(t, playlist.Name, strconv.Itoa(time.Now().Year()))
+}
+
+func TestLoad_NameFromDirective(t *testing.T) {
+	path := createTempFile(t, "backup.txt", "#name: Friday Mix\n#description: Weekend songs\nToto - Africa")
+
+	playlist, err := Load(path, Options{})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Friday Mix", playlist.Name)
+	assert.Equal(t, "Weekend songs", playlist.Description)
+}
+
+func TestLoad_FileNotFound(t *testing.T) {
+	playlist, err := Load("nonexistent.txt", Options{})
+
+	assert.Nil(t, playlist)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to open file")
+}
+
+func TestLoad_EmptyFile(t *testing.T) {
+	content := `# Only comments
+// Nothing else`
+
+	path := createTempFile(t, "empty.txt", content)
+
+	playlist, err := Load(path, Options{})
+
+	assert.Nil(t, playlist)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no songs found")
+}
+
+func TestLoad_UnknownExtensionReadsText(t *testing.T) {
+	path := createTempFile(t, "songs.playlist", "Queen - Bohemian Rhapsody")
+
+	playlist, err := Load(path, Options{})
+
+	require.NoError(t, err)
+	require.Len(t, playlist.Tracks, 1)
+	assert.Equal(t, "Queen", playlist.Tracks[0].Artist)
+}
+
+func TestLoad_NamedFormat(t *testing.T) {
+	path := createTempFile(t, "songs.data", "Queen - Bohemian Rhapsody")
+
+	playlist, err := Load(path, Options{Format: "TXT"})
+	require.NoError(t, err)
+	assert.Len(t, playlist.Tracks, 1)
+
+	_, err = Load(path, Options{Format: "wav"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown playlist format 'wav'")
+}
+
+func TestSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mix.txt")
+	playlist := &Playlist{
+		Name:   "Mix",
+		Tracks: []Track{{Artist: "Queen", Title: "Bohemian Rhapsody"}},
+	}
+
+	require.NoError(t, Save(path, playlist, Options{}))
+
+	loaded, err := Load(path, Options{})
+	require.NoError(t, err)
+	require.Len(t, loaded.Tracks, 1)
+	assert.Equal(t, "Queen", loaded.Tracks[0].Artist)
+	assert.Equal(t, "Bohemian Rhapsody", loaded.Tracks[0].Title)
+}
+
+func TestForPath(t *testing.T) {
+	assert.Equal(t, "txt", ForPath("songs.txt").Name)
+	assert.Equal(t, "txt", ForPath("songs.LIST").Name)
+	assert.Equal(t, "txt", ForPath("songs").Name)
+}
+
+func TestIsPlaylistFile(t *testing.T) {
+	assert.True(t, IsPlaylistFile("songs.txt"))
+	assert.True(t, IsPlaylistFile("/music/Road Trip.M3U8"))
+	assert.True(t, IsPlaylistFile("ranking.csv"))
+	assert.False(t, IsPlaylistFile("README.md"))
+	assert.False(t, IsPlaylistFile("songs"))
+}
+
+func TestRegister(t *testing.T) {
+	Register(Format{Name: "test", Extensions: []string{".tst"}, Writer: TextWriter{}})
+	defer delete(formats, "test")
+
+	assert.Equal(t, "test", ForPath("songs.tst").Name)
+	assert.Contains(t, Names(), "test")
+
+	format, err := Lookup("test")
+	require.NoError(t, err)
+	assert.Equal(t, ".tst", format.Extension())
+
+	_, err = Load(createTempFile(t, "songs.tst", "Queen - Bohemian Rhapsody"), Options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reading test playlists is not supported")
+}
+
+func TestRead(t *testing.T) {
+	playlist, err := Read(strings.NewReader("Queen - Bohemian Rhapsody\nToto - Africa\n"), Options{})
+	require.NoError(t, err)
+
+	require.Len(t, playlist.Tracks, 2)
+	assert.Equal(t, "Toto", playlist.Tracks[1].Artist)
+	assert.Equal(t, "From stdin (line 2)", playlist.Tracks[1].Reason)
+	assert.True(t, strings.HasPrefix(playlist.Name, "Playlist ("))
+	assert.Equal(t, "Playlist loaded from stdin (2 songs)", playlist.Description)
+}
+
+func TestRead_BracketedText(t *testing.T) {
+	playlist, err := Read(strings.NewReader("[Intro] - Artist\nToto - Africa\n"), Options{})
+	require.NoError(t, err)
+
+	require.Len(t, playlist.Tracks, 2)
+	assert.Equal(t, "[Intro]", playlist.Tracks[0].Artist)
+	assert.Equal(t, "Africa", playlist.Tracks[1].Title)
+}
+
+func TestRead_NamedFormat(t *testing.T) {
+	playlist, err := Read(strings.NewReader("Band,Song\nToto,Africa\n"), Options{Format: "csv"})
+	require.NoError(t, err)
+
+	require.Len(t, playlist.Tracks, 1)
+	assert.Equal(t, "Africa", playlist.Tracks[0].Title)
+}
+
+func TestRead_Empty(t *testing.T) {
+	_, err := Read(strings.NewReader("# nothing here\n"), Options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no songs found in stdin")
+}
+
+func TestDetect(t *testing.T) {
+	tests := map[string]string{
+		`{"playlist_name": "Mix", "songs": []}`:      "json",
+		"  [\n  {\"artist\": \"Toto\"}\n]":           "json",
+		"---\nplaylist_name: Mix\n":                  "yaml",
+		"songs:\n  - artist: Toto\n":                 "yaml",
+		"\ufeff#EXTM3U\n#EXTINF:-1,Toto - Africa\n":  "m3u8",
+		`<?xml version="1.0"?><playlist></playlist>`: "xspf",
+		"Queen - Bohemian Rhapsody\n":                "txt",
+		"[Intro] - Artist\nToto - Africa\n":          "txt",
+		"---\nToto - Africa\nQueen - Innuendo\n":     "txt",
+		"{not json\n":                                "txt",
+		"":                                           "txt",
+	}
+	for content, want := range tests {
+		assert.Equal(t, want, Detect([]byte(content)).Name, "content %q", content)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	playlist := &Playlist{Name: "Mix", Tracks: []Track{{Artist: "Toto", Title: "Africa"}}}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, playlist, Options{}))
+	assert.Contains(t, buf.String(), "Toto - Africa")
+
+	buf.Reset()
+	require.NoError(t, Write(&buf, playlist, Options{Format: "json"}))
+	assert.Contains(t, buf.String(), `"playlist_name"`)
+
+	err := Write(&buf, playlist, Options{Format: "wav"})
+	require.Error(t, err)
+}
+
+func TestNameFromPath(t *testing.T) {
+	assert.Equal(t, "Metal Songs", NameFromPath("/music/metal-songs.txt"))
+	assert.Equal(t, "Road Trip", NameFromPath(`C:\music\road_trip.list`))
+	assert.Equal(t, "Mix", NameFromPath("mix"))
+}
+
+// Helper function to create temporary test files
+func createTempFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+// Benchmark tests for file parsing performance
+func BenchmarkTextReader(b *testing.B) {
+	var lines []string
+	for i := 0; i < 1000; i++ {
+		lines = append(lines, "Artist "+string(rune(i%26+65))+" - Song "+string(rune(i%26+65)))
+	}
+	content := []byte(strings.Join(lines, "\n"))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := TextReader{}.Read(bytes.NewReader(content))
+		require.NoError(b, err)
+	}
+}
@@ -0,0 +1,193 @@
+package playlistfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"auto-spotify/internal/spotifyid"
+
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	Register(Format{
+		Name:       "json",
+		Extensions: []string{".json"},
+		Reader:     JSONReader{},
+		Writer:     JSONWriter{},
+	})
+	Register(Format{
+		Name:       "yaml",
+		Extensions: []string{".yaml", ".yml"},
+		Reader:     YAMLReader{},
+		Writer:     YAMLWriter{},
+	})
+}
+
+// document is the JSON and YAML form of a playlist. Its fields match the
+// playlists the AI answers with, so those can be imported as they are.
+type document struct {
+	PlaylistName string         `json:"playlist_name,omitempty" yaml:"playlist_name,omitempty"`
+	Description  string         `json:"description,omitempty" yaml:"description,omitempty"`
+	Public       *bool          `json:"public,omitempty" yaml:"public,omitempty"`
+	SourceID     string         `json:"source_id,omitempty" yaml:"source_id,omitempty"`
+	Market       string         `json:"market,omitempty" yaml:"market,omitempty"`
+	Songs        []documentSong `json:"songs" yaml:"songs"`
+}
+
+type documentSong struct {
+	Artist      string `json:"artist,omitempty" yaml:"artist,omitempty"`
+	Title       string `json:"title,omitempty" yaml:"title,omitempty"`
+	Album       string `json:"album,omitempty" yaml:"album,omitempty"`
+	Year        int    `json:"year,omitempty" yaml:"year,omitempty"`
+	Reason      string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	URI         string `json:"uri,omitempty" yaml:"uri,omitempty"`
+	Section     string `json:"section,omitempty" yaml:"section,omitempty"`
+	Comment     string `json:"comment,omitempty" yaml:"comment,omitempty"`
+	Unavailable bool   `json:"unavailable,omitempty" yaml:"unavailable,omitempty"`
+}
+
+func newDocument(playlist *Playlist) document {
+	doc := document{
+		PlaylistName: playlist.Name,
+		Description:  playlist.Description,
+		Public:       playlist.Public,
+		SourceID:     playlist.SourceID,
+		Market:       playlist.Market,
+		Songs:        make([]documentSong, 0, len(playlist.Tracks)),
+	}
+	for _, track := range playlist.Tracks {
+		doc.Songs = append(doc.Songs, documentSong{
+			Artist:      track.Artist,
+			Title:       track.Title,
+			Album:       track.Album,
+			Year:        track.Year,
+			Reason:      track.Reason,
+			URI:         track.URI,
+			Section:     track.Section,
+			Comment:     track.Comment,
+			Unavailable: track.Unavailable,
+		})
+	}
+	return doc
+}
+
+// playlist converts the document, skipping songs without a title or a valid
+// track URI with a warning
+func (doc document) playlist() *Playlist {
+	playlist := &Playlist{
+		Name:        doc.PlaylistName,
+		Description: doc.Description,
+		Public:      doc.Public,
+		Market:      doc.Market,
+	}
+
+	if doc.SourceID != "" {
+		if warning := applyDirective(playlist, directiveSourceID, doc.SourceID); warning != "" {
+			playlist.Warnings = append(playlist.Warnings, Warning{Message: warning})
+		}
+	}
+
+	for i, song := range doc.Songs {
+		track := Track{
+			Artist:      song.Artist,
+			Title:       song.Title,
+			Album:       song.Album,
+			Year:        song.Year,
+			Reason:      song.Reason,
+			Section:     song.Section,
+			Comment:     song.Comment,
+			Unavailable: song.Unavailable,
+		}
+
+		if song.URI != "" {
+			uri, ok := trackURI(song.URI)
+			if !ok {
+				// Bare IDs are fine here, there is no song text to mistake them for
+				if id, err := spotifyid.Parse("track", song.URI); err == nil {
+					uri, ok = trackURIPrefix+id, true
+				}
+			}
+			if ok {
+				track.URI = uri
+			} else {
+				playlist.Warnings = append(playlist.Warnings, Warning{
+					Message: fmt.Sprintf("song %d: '%s' is not a Spotify track URI, URL or ID", i+1, song.URI),
+				})
+			}
+		}
+
+		if track.Title == "" && track.URI == "" {
+			playlist.Warnings = append(playlist.Warnings, Warning{
+				Message: fmt.Sprintf("song %d has no title or track URI, skipping it", i+1),
+			})
+			continue
+		}
+		playlist.Tracks = append(playlist.Tracks, track)
+	}
+
+	return playlist
+}
+
+// JSONReader reads playlists in the JSON form the AI answers with, where
+// songs may also carry a Spotify track URI. A top-level array is read as
+// the songs of a playlist without a name, like jq '.songs[:10]' leaves.
+type JSONReader struct{}
+
+// Read parses the playlist in r
+func (JSONReader) Read(r io.Reader) (*Playlist, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON playlist: %w", err)
+	}
+
+	var doc document
+	target := any(&doc)
+	if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+		target = &doc.Songs
+	}
+	if err := json.Unmarshal(raw, target); err != nil {
+		return nil, fmt.Errorf("invalid JSON playlist: %w", err)
+	}
+	return doc.playlist(), nil
+}
+
+// JSONWriter writes playlists as indented JSON
+type JSONWriter struct{}
+
+// Write writes the playlist to w
+func (JSONWriter) Write(w io.Writer, playlist *Playlist) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(newDocument(playlist))
+}
+
+// YAMLReader reads playlists with the same fields as JSONReader from YAML
+type YAMLReader struct{}
+
+// Read parses the playlist in r
+func (YAMLReader) Read(r io.Reader) (*Playlist, error) {
+	var doc document
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		if err == io.EOF {
+			return &Playlist{}, nil
+		}
+		return nil, fmt.Errorf("invalid YAML playlist: %w", err)
+	}
+	return doc.playlist(), nil
+}
+
+// YAMLWriter writes playlists as YAML
+type YAMLWriter struct{}
+
+// Write writes the playlist to w
+func (YAMLWriter) Write(w io.Writer, playlist *Playlist) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(newDocument(playlist)); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
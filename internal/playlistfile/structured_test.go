@@ -0,0 +1,114 @@
+package playlistfile
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func samplePlaylist() *Playlist {
+	public := false
+	return &Playlist{
+		Name:        "Friday Mix",
+		Description: "Songs for the weekend",
+		Public:      &public,
+		SourceID:    "37i9dQZF1DXcBWIGoYBM5M",
+		Market:      "DE",
+		Tracks: []Track{
+			{Artist: "Toto", Title: "Africa", Album: "Toto IV", Year: 1982, URI: "spotify:track:2374M0fQpWi3dLnB54qaLX", Section: "Warm-up"},
+			{Artist: "Daft Punk", Title: "One More Time", Reason: "Peak time anthem", Comment: "play loud", Unavailable: true},
+		},
+	}
+}
+
+func TestJSON_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, JSONWriter{}.Write(&buf, samplePlaylist()))
+
+	assert.Contains(t, buf.String(), `"playlist_name": "Friday Mix"`)
+	assert.Contains(t, buf.String(), `"uri": "spotify:track:2374M0fQpWi3dLnB54qaLX"`)
+
+	read, err := JSONReader{}.Read(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, samplePlaylist(), read)
+}
+
+func TestYAML_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, YAMLWriter{}.Write(&buf, samplePlaylist()))
+
+	assert.Contains(t, buf.String(), "playlist_name: Friday Mix")
+	assert.Contains(t, buf.String(), "  - artist: Toto")
+
+	read, err := YAMLReader{}.Read(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, samplePlaylist(), read)
+}
+
+func TestJSONReader_AIResponse(t *testing.T) {
+	content := `{
+  "playlist_name": "Road Trip",
+  "description": "Songs for the highway",
+  "songs": [
+    {"artist": "Toto", "title": "Africa", "reason": "Sing-along classic"},
+    {"uri": "https://open.spotify.com/track/7ouMYWpwJ422jRcDASZB7P"},
+    {"uri": "4uLU6hMCjMI75M1A2tKUQC", "title": "Bare ID"},
+    {"artist": "Nobody"},
+    {"title": "Broken", "uri": "spotify:album:nope"}
+  ]
+}`
+
+	playlist, err := JSONReader{}.Read(strings.NewReader(content))
+
+	require.NoError(t, err)
+	assert.Equal(t, "Road Trip", playlist.Name)
+	require.Len(t, playlist.Tracks, 4)
+	assert.Equal(t, "Sing-along classic", playlist.Tracks[0].Reason)
+	assert.Equal(t, "spotify:track:7ouMYWpwJ422jRcDASZB7P", playlist.Tracks[1].URI)
+	assert.Equal(t, "spotify:track:4uLU6hMCjMI75M1A2tKUQC", playlist.Tracks[2].URI)
+	assert.Equal(t, "Broken", playlist.Tracks[3].Title)
+	assert.Empty(t, playlist.Tracks[3].URI)
+
+	require.Len(t, playlist.Warnings, 2)
+	assert.Equal(t, "song 4 has no title or track URI, skipping it", playlist.Warnings[0].String())
+	assert.Contains(t, playlist.Warnings[1].String(), "song 5: 'spotify:album:nope' is not a Spotify track")
+}
+
+func TestJSONReader_SongArray(t *testing.T) {
+	content := `[
+		{"artist": "Metallica", "title": "One"},
+		{"uri": "spotify:track:4uLU6hMCjMI75M1A2tKUQC"}
+	]`
+
+	playlist, err := JSONReader{}.Read(strings.NewReader(content))
+
+	require.NoError(t, err)
+	assert.Empty(t, playlist.Name)
+	require.Len(t, playlist.Tracks, 2)
+	assert.Equal(t, "Metallica", playlist.Tracks[0].Artist)
+	assert.Equal(t, "One", playlist.Tracks[0].Title)
+	assert.Equal(t, "spotify:track:4uLU6hMCjMI75M1A2tKUQC", playlist.Tracks[1].URI)
+}
+
+func TestJSONReader_Invalid(t *testing.T) {
+	_, err := JSONReader{}.Read(strings.NewReader(`{"songs": [`))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid JSON playlist")
+}
+
+func TestYAMLReader_Invalid(t *testing.T) {
+	_, err := YAMLReader{}.Read(strings.NewReader("songs: [unclosed"))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid YAML playlist")
+}
+
+func TestForPath_Structured(t *testing.T) {
+	assert.Equal(t, "json", ForPath("mix.JSON").Name)
+	assert.Equal(t, "yaml", ForPath("mix.yml").Name)
+	assert.Equal(t, "yaml", ForPath("mix.yaml").Name)
+}
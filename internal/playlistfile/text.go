@@ -0,0 +1,121 @@
+package playlistfile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func init() {
+	Register(Format{
+		Name:       "txt",
+		Extensions: []string{".txt", ".list"},
+		Reader:     TextReader{},
+		Writer:     TextWriter{},
+	})
+}
+
+// TextReader reads plain text playlists with one song per line, written as
+// "Artist - Title", "Artist: Title" or just "Title", optionally
+// followed by "[Album, Year]", a Spotify track URI or URL and a # comment.
+// Lines starting with # or // are comments and "## Name" starts a section.
+// Directives like "#name: My Mix" before the first song set the playlist's
+// name, description, visibility and the playlist it was exported from.
+// Lines that could be read in more than one way are reported as warnings.
+type TextReader struct{}
+
+// Read parses the songs in r
+func (TextReader) Read(r io.Reader) (*Playlist, error) {
+	playlist := &Playlist{}
+	scanner := bufio.NewScanner(r)
+
+	section := ""
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, sectionPrefix) {
+			section = strings.TrimSpace(strings.TrimPrefix(line, sectionPrefix))
+			continue
+		}
+
+		if key, value, ok := parseDirective(line); ok && len(playlist.Tracks) == 0 {
+			if warning := applyDirective(playlist, key, value); warning != "" {
+				playlist.Warnings = append(playlist.Warnings, Warning{Line: lineNum, Message: warning})
+			}
+			continue
+		}
+
+		// Skip empty lines and comments
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		track, warning := parseLine(line)
+		if track.Title == "" && track.URI == "" {
+			if warning == "" {
+				warning = "no song found, skipping the line"
+			}
+			playlist.Warnings = append(playlist.Warnings, Warning{Line: lineNum, Message: warning})
+			continue
+		}
+		if warning != "" {
+			playlist.Warnings = append(playlist.Warnings, Warning{Line: lineNum, Message: warning})
+		}
+
+		track.Section = section
+		track.Line = lineNum
+		playlist.Tracks = append(playlist.Tracks, track)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return playlist, nil
+}
+
+// sectionPrefix starts a section header line
+const sectionPrefix = "## "
+
+// TextWriter writes playlists as song lines that TextReader reads back as the
+// same tracks, with the playlist details in header directives
+type TextWriter struct{}
+
+// Write writes the playlist to w
+func (TextWriter) Write(w io.Writer, playlist *Playlist) error {
+	bw := bufio.NewWriter(w)
+
+	writeDirectives(bw, playlist)
+	fmt.Fprintf(bw, "# %d tracks\n", len(playlist.Tracks))
+
+	unavailable := 0
+	for _, track := range playlist.Tracks {
+		if track.Unavailable {
+			unavailable++
+		}
+	}
+	if unavailable > 0 {
+		fmt.Fprintf(bw, "# %d tracks not available in %s\n", unavailable, playlist.Market)
+	}
+	fmt.Fprintf(bw, "# Exported from Spotify\n\n")
+
+	section := ""
+	for i, track := range playlist.Tracks {
+		if track.Section != "" && track.Section != section {
+			section = track.Section
+			if i > 0 {
+				fmt.Fprintln(bw)
+			}
+			fmt.Fprintf(bw, "%s%s\n", sectionPrefix, section)
+		}
+		if track.Unavailable && track.Comment == "" {
+			track.Comment = "not available in " + playlist.Market
+		}
+		fmt.Fprintln(bw, formatLine(track))
+	}
+
+	return bw.Flush()
+}
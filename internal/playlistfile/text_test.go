@@ -0,0 +1,252 @@
+package playlistfile
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextReader_Formats(t *testing.T) {
+	content := `# Test playlist
+Metallica - Master of Puppets
+Iron Maiden: Run to the Hills
+Enter Sandman by Metallica
+Just a Song Title
+
+// Another comment
+AC/DC - Thunderstruck`
+
+	playlist, err := TextReader{}.Read(strings.NewReader(content))
+
+	require.NoError(t, err)
+	require.Len(t, playlist.Tracks, 5)
+
+	tracks := playlist.Tracks
+
+	// "Artist - Song Title"
+	assert.Equal(t, "Metallica", tracks[0].Artist)
+	assert.Equal(t, "Master of Puppets", tracks[0].Title)
+	assert.Equal(t, 2, tracks[0].Line)
+
+	// "Artist: Song Title"
+	assert.Equal(t, "Iron Maiden", tracks[1].Artist)
+	assert.Equal(t, "Run to the Hills", tracks[1].Title)
+
+	// "Song Title by Artist" is ambiguous and read as a title
+	assert.Empty(t, tracks[2].Artist)
+	assert.Equal(t, "Enter Sandman by Metallica", tracks[2].Title)
+	require.Len(t, playlist.Warnings, 1)
+	assert.Equal(t, 4, playlist.Warnings[0].Line)
+
+	// Just song title
+	assert.Empty(t, tracks[3].Artist)
+	assert.Equal(t, "Just a Song Title", tracks[3].Title)
+
+	// Another "Artist - Song Title"
+	assert.Equal(t, "AC/DC", tracks[4].Artist)
+	assert.Equal(t, "Thunderstruck", tracks[4].Title)
+	assert.Equal(t, 8, tracks[4].Line)
+}
+
+func TestTextReader_DifferentFormats(t *testing.T) {
+	tests := []struct {
+		name           string
+		line           string
+		expectedArtist string
+		expectedTitle  string
+	}{
+		{
+			name:           "dash format",
+			line:           "The Beatles - Hey Jude",
+			expectedArtist: "The Beatles",
+			expectedTitle:  "Hey Jude",
+		},
+		{
+			name:           "colon format",
+			line:           "Queen: Bohemian Rhapsody",
+			expectedArtist: "Queen",
+			expectedTitle:  "Bohemian Rhapsody",
+		},
+		{
+			name:           "by format is read as a title",
+			line:           "Stairway to Heaven by Led Zeppelin",
+			expectedArtist: "",
+			expectedTitle:  "Stairway to Heaven by Led Zeppelin",
+		},
+		{
+			name:           "title only",
+			line:           "Hotel California",
+			expectedArtist: "",
+			expectedTitle:  "Hotel California",
+		},
+		{
+			name:           "with extra spaces",
+			line:           "  Pink Floyd   -   Comfortably Numb  ",
+			expectedArtist: "Pink Floyd",
+			expectedTitle:  "Comfortably Numb",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			playlist, err := TextReader{}.Read(strings.NewReader(tt.line))
+
+			require.NoError(t, err)
+			require.Len(t, playlist.Tracks, 1)
+
+			track := playlist.Tracks[0]
+			assert.Equal(t, tt.expectedArtist, track.Artist)
+			assert.Equal(t, tt.expectedTitle, track.Title)
+		})
+	}
+}
+
+func TestTextWriter(t *testing.T) {
+	public := true
+	playlist := &Playlist{
+		Name:        "My Mix",
+		Description: "Songs for the road",
+		Public:      &public,
+		SourceID:    "37i9dQZF1DXcBWIGoYBM5M",
+		Market:      "DE",
+		Tracks: []Track{
+			{Artist: "Queen", Title: "Bohemian Rhapsody"},
+			{Artist: "The Beatles", Title: "Hey Jude", Unavailable: true},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, TextWriter{}.Write(&buf, playlist))
+
+	expected := `#name: My Mix
+#description: Songs for the road
+#public: true
+#source-id: 37i9dQZF1DXcBWIGoYBM5M
+# 2 tracks
+# 1 tracks not available in DE
+# Exported from Spotify
+
+Queen - Bohemian Rhapsody
+The Beatles - Hey Jude # not available in DE
+`
+	assert.Equal(t, expected, buf.String())
+
+	// The written file reads back as the same songs
+	read, err := TextReader{}.Read(&buf)
+	require.NoError(t, err)
+	require.Len(t, read.Tracks, 2)
+	assert.Equal(t, "Hey Jude", read.Tracks[1].Title)
+}
+
+func TestTextReader_Warnings(t *testing.T) {
+	content := `# Mix
+Jay-Z - 99 Problems
+AC-DC
+Ben E. King - Stand by Me
+Queen - Bohemian Rhapsody - Remastered 2011`
+
+	playlist, err := TextReader{}.Read(strings.NewReader(content))
+
+	require.NoError(t, err)
+	require.Len(t, playlist.Tracks, 4)
+	assert.Equal(t, "Jay-Z", playlist.Tracks[0].Artist)
+	assert.Empty(t, playlist.Tracks[1].Artist)
+	assert.Equal(t, "AC-DC", playlist.Tracks[1].Title)
+	assert.Equal(t, "Stand by Me", playlist.Tracks[2].Title)
+
+	require.Len(t, playlist.Warnings, 2)
+	assert.Equal(t, 3, playlist.Warnings[0].Line)
+	assert.Equal(t, 5, playlist.Warnings[1].Line)
+	assert.Contains(t, playlist.Warnings[1].String(), "line 5: more than one '-' separator")
+}
+
+func TestTextReader_RichSyntax(t *testing.T) {
+	content := `# Party
+## Warm-up
+Toto - Africa [Toto IV, 1982]
+spotify:track:4uLU6hMCjMI75M1A2tKUQC
+https://open.spotify.com/intl-de/track/7ouMYWpwJ422jRcDASZB7P?si=abc   // Paste from the app
+
+## Peak time
+Daft Punk - One More Time [2000] # Play loud
+[Discovery]
+`
+
+	playlist, err := TextReader{}.Read(strings.NewReader(content))
+
+	require.NoError(t, err)
+	require.Len(t, playlist.Tracks, 4)
+
+	assert.Equal(t, Track{Artist: "Toto", Title: "Africa", Album: "Toto IV", Year: 1982, Section: "Warm-up", Line: 3}, playlist.Tracks[0])
+	assert.Equal(t, Track{URI: "spotify:track:4uLU6hMCjMI75M1A2tKUQC", Section: "Warm-up", Line: 4}, playlist.Tracks[1])
+	assert.Equal(t, Track{URI: "spotify:track:7ouMYWpwJ422jRcDASZB7P", Comment: "Paste from the app", Section: "Warm-up", Line: 5}, playlist.Tracks[2])
+	assert.Equal(t, Track{Artist: "Daft Punk", Title: "One More Time", Year: 2000, Comment: "Play loud", Section: "Peak time", Line: 8}, playlist.Tracks[3])
+
+	require.Len(t, playlist.Warnings, 1)
+	assert.Equal(t, 9, playlist.Warnings[0].Line)
+	assert.Contains(t, playlist.Warnings[0].Message, "only an album")
+}
+
+func TestTextWriter_RoundTrip(t *testing.T) {
+	playlist := &Playlist{
+		Name: "Round Trip",
+		Tracks: []Track{
+			{Artist: "Queen", Title: "Bohemian Rhapsody - Remastered 2011", Album: "A Night at the Opera (2011 Remaster)", Year: 1975, URI: "spotify:track:4u7EnebtmKWzUH433cf5Qv"},
+			{Artist: "Taylor Swift", Title: "Style", Album: "1989", URI: "spotify:track:0ug5NqcwcFR2xrfTkc7k8e"},
+			{Artist: "Garbage", Title: "#1 Crush", Album: "Greatest Hits, Vol. 2", Year: 1996},
+			{Artist: "Nirvana", Title: "Lithium [Live]", Album: "Live at Reading"},
+			{Title: "Stand by Me", Year: 1961},
+			{URI: "spotify:track:7ouMYWpwJ422jRcDASZB7P", Section: "Encore"},
+			{Artist: "ABBA", Title: "Mamma Mia", Section: "Encore", Comment: "everyone sings"},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, TextWriter{}.Write(&buf, playlist))
+
+	read, err := TextReader{}.Read(&buf)
+	require.NoError(t, err)
+	assert.Empty(t, read.Warnings)
+	assert.Equal(t, "Round Trip", read.Name)
+	require.Len(t, read.Tracks, len(playlist.Tracks))
+	for i, track := range read.Tracks {
+		track.Line = 0
+		assert.Equal(t, playlist.Tracks[i], track)
+	}
+}
+
+func TestTextReader_Directives(t *testing.T) {
+	content := `#name: Friday Mix
+#Description: Songs for the weekend
+#public: yes
+#source-id: https://open.spotify.com/playlist/37i9dQZF1DXcBWIGoYBM5M
+#curator: me
+# name: not a directive
+Toto - Africa
+#name: Too late
+#public: false`
+
+	playlist, err := TextReader{}.Read(strings.NewReader(content))
+
+	require.NoError(t, err)
+	assert.Equal(t, "Friday Mix", playlist.Name)
+	assert.Equal(t, "Songs for the weekend", playlist.Description)
+	assert.Equal(t, "37i9dQZF1DXcBWIGoYBM5M", playlist.SourceID)
+	assert.Nil(t, playlist.Public)
+	require.Len(t, playlist.Tracks, 1)
+
+	require.Len(t, playlist.Warnings, 1)
+	assert.Equal(t, 3, playlist.Warnings[0].Line)
+	assert.Contains(t, playlist.Warnings[0].Message, "must be true or false")
+}
+
+func TestTextReader_PublicDirective(t *testing.T) {
+	playlist, err := TextReader{}.Read(strings.NewReader("#public: false\nToto - Africa"))
+
+	require.NoError(t, err)
+	require.NotNil(t, playlist.Public)
+	assert.False(t, *playlist.Public)
+}
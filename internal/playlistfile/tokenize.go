@@ -0,0 +1,249 @@
+package playlistfile
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// separator kinds in order of preference
+type sepKind int
+
+const (
+	sepNone  sepKind = iota
+	sepDash          // " - ", or an en/em dash with or without spaces
+	sepColon         // ": "
+	sepBy            // " by "
+)
+
+// token is a piece of a song line: either text or a separator
+type token struct {
+	text string  // Text with quotes and escapes resolved, or the separator as written
+	sep  sepKind // sepNone for text
+}
+
+// tokenizeLine splits a song line into text and separators. Separators inside
+// double quotes or escaped with a backslash are kept as text. It reports
+// whether the quotes in the line are balanced.
+func tokenizeLine(line string) ([]token, bool) {
+	runes := []rune(line)
+	if strings.Count(unescaped(runes), `"`)%2 != 0 {
+		// An unbalanced quote is part of the title, like in 12" Mix
+		return tokenize(runes, false), false
+	}
+	return tokenize(runes, true), true
+}
+
+// unescaped returns the line with escaped characters removed, for counting quotes
+func unescaped(runes []rune) string {
+	var b strings.Builder
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '\\' {
+			i++
+			continue
+		}
+		b.WriteRune(runes[i])
+	}
+	return b.String()
+}
+
+func tokenize(runes []rune, quotes bool) []token {
+	var tokens []token
+	var text strings.Builder
+	inQuote := false
+
+	flush := func() {
+		tokens = append(tokens, token{text: text.String()})
+		text.Reset()
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case r == '\\' && i+1 < len(runes):
+			i++
+			text.WriteRune(runes[i])
+			continue
+		case r == '"' && quotes:
+			inQuote = !inQuote
+			continue
+		case inQuote:
+			text.WriteRune(r)
+			continue
+		}
+
+		if kind, width := separatorAt(runes, i); kind != sepNone {
+			flush()
+			tokens = append(tokens, token{text: string(runes[i : i+width]), sep: kind})
+			i += width - 1
+			continue
+		}
+		text.WriteRune(r)
+	}
+	flush()
+
+	return tokens
+}
+
+// separatorAt reports the separator starting at runes[i] and its length in runes
+func separatorAt(runes []rune, i int) (sepKind, int) {
+	r := runes[i]
+
+	// En and em dashes never appear inside names by accident
+	if r == '–' || r == '—' {
+		return sepDash, 1
+	}
+
+	if r == ':' && i+1 < len(runes) && unicode.IsSpace(runes[i+1]) {
+		return sepColon, 1
+	}
+
+	if !unicode.IsSpace(r) {
+		return sepNone, 0
+	}
+
+	// Whitespace around a hyphen or the word "by"
+	j := i
+	for j < len(runes) && unicode.IsSpace(runes[j]) {
+		j++
+	}
+	word := ""
+	switch {
+	case j < len(runes) && runes[j] == '-':
+		word = "-"
+	case j+1 < len(runes) && runes[j] == 'b' && runes[j+1] == 'y':
+		word = "by"
+	default:
+		return sepNone, 0
+	}
+
+	k := j + len(word)
+	if k >= len(runes) || !unicode.IsSpace(runes[k]) {
+		return sepNone, 0
+	}
+	for k < len(runes) && unicode.IsSpace(runes[k]) {
+		k++
+	}
+
+	if word == "-" {
+		return sepDash, k - i
+	}
+	return sepBy, k - i
+}
+
+// parseSongLine splits a song line into artist and title. It prefers " - "
+// (or an en/em dash) over ": ". A line whose only separator is " by " is
+// read as a title with a warning, since "Stand by Me" and "Song by Artist"
+// can't be told apart.
+// Quote the artist or title, or escape a character with a backslash, to keep
+// a separator in it. A warning is returned when the line could be read in
+// more than one way.
+func parseSongLine(line string) (artist, title, warning string) {
+	tokens, balanced := tokenizeLine(line)
+	if !balanced {
+		warning = "unbalanced double quote, reading it as part of the song"
+	}
+
+	counts := make(map[sepKind]int)
+	for _, tok := range tokens {
+		counts[tok.sep]++
+	}
+
+	kind := sepNone
+	for _, candidate := range []sepKind{sepDash, sepColon} {
+		if counts[candidate] > 0 {
+			kind = candidate
+			break
+		}
+	}
+
+	if kind == sepNone {
+		title = joinTokens(tokens)
+		switch {
+		case warning != "":
+		case counts[sepBy] > 0:
+			warning = fmt.Sprintf("'%s' is ambiguous, ' by ' may be part of the title or come before the artist; reading it all as the title (write 'Artist - Title' to split it)", title)
+		case strings.Contains(title, "-"):
+			warning = fmt.Sprintf("'%s' has no ' - ' between artist and title, reading it all as the title", title)
+		}
+		return "", title, warning
+	}
+
+	// The first separator wins
+	split := -1
+	for i, tok := range tokens {
+		if tok.sep == kind {
+			split = i
+			break
+		}
+	}
+	artist, title = joinTokens(tokens[:split]), joinTokens(tokens[split+1:])
+
+	if counts[kind] > 1 && warning == "" {
+		warning = fmt.Sprintf("more than one '%s' separator, reading artist '%s' and title '%s' (quote the artist or title to be explicit)",
+			strings.TrimSpace(sepText(kind)), artist, title)
+	}
+
+	if artist == "" || title == "" {
+		// "- Title" or "Artist -": keep whatever is there as the title
+		return "", strings.TrimSpace(artist + " " + title), warning
+	}
+
+	return artist, title, warning
+}
+
+// joinTokens rebuilds text from tokens, keeping separators as written
+func joinTokens(tokens []token) string {
+	var b strings.Builder
+	for _, tok := range tokens {
+		b.WriteString(tok.text)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func sepText(kind sepKind) string {
+	switch kind {
+	case sepDash:
+		return " - "
+	case sepColon:
+		return ": "
+	case sepBy:
+		return " by "
+	}
+	return ""
+}
+
+// formatSongLine writes an "Artist - Title" line that parseSongLine reads
+// back as the same artist and title, quoting fields where needed
+func formatSongLine(artist, title string) string {
+	return quoteField(artist) + " - " + quoteField(title)
+}
+
+// quoteField quotes s if it contains a dash separator or any other syntax of
+// a song line: quotes, backslashes, brackets, comments or a track link
+func quoteField(s string) string {
+	needsQuotes := strings.ContainsAny(s, `"\[]#`) || strings.Contains(s, "//") ||
+		s != strings.TrimSpace(s)
+	if fields := strings.Fields(s); len(fields) > 0 {
+		_, isLink := trackURI(fields[len(fields)-1])
+		needsQuotes = needsQuotes || isLink
+	}
+
+	runes := []rune(s)
+	for i := 0; i < len(runes) && !needsQuotes; i++ {
+		if kind, _ := separatorAt(runes, i); kind == sepDash {
+			needsQuotes = true
+		}
+	}
+	if !needsQuotes {
+		return s
+	}
+	return quote(s)
+}
+
+// quote wraps s in double quotes, escaping quotes and backslashes in it
+func quote(s string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+	return `"` + escaped + `"`
+}
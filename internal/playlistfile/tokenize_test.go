@@ -0,0 +1,86 @@
+package playlistfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSongLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		artist  string
+		title   string
+		warning string
+	}{
+		{name: "dash", line: "The Beatles - Hey Jude", artist: "The Beatles", title: "Hey Jude"},
+		{name: "hyphenated artist", line: "Jay-Z - 99 Problems", artist: "Jay-Z", title: "99 Problems"},
+		{name: "hyphenated title", line: "OutKast - Hey-Ya", artist: "OutKast", title: "Hey-Ya"},
+		{name: "hyphen without spaces", line: "AC-DC", title: "AC-DC", warning: "no ' - ' between artist and title"},
+		{name: "en dash", line: "Beyoncé – Halo", artist: "Beyoncé", title: "Halo"},
+		{name: "em dash without spaces", line: "Beyoncé—Halo", artist: "Beyoncé", title: "Halo"},
+		{name: "extra spaces", line: "Pink Floyd   -   Comfortably Numb", artist: "Pink Floyd", title: "Comfortably Numb"},
+		{name: "colon", line: "Queen: Bohemian Rhapsody", artist: "Queen", title: "Bohemian Rhapsody"},
+		{name: "dash preferred over colon", line: "John Williams - Star Wars: Main Title", artist: "John Williams", title: "Star Wars: Main Title"},
+		{name: "dash preferred over by", line: "Ben E. King - Stand by Me", artist: "Ben E. King", title: "Stand by Me"},
+		{name: "by in title", line: "Stand by Me", title: "Stand by Me", warning: "'Stand by Me' is ambiguous"},
+		{name: "by before artist", line: "Song by Artist", title: "Song by Artist", warning: "'Song by Artist' is ambiguous"},
+		{name: "several by", line: "Stand by Me by Ben E. King", title: "Stand by Me by Ben E. King", warning: "is ambiguous"},
+		{name: "title only", line: "Hotel California", title: "Hotel California"},
+		{name: "quoted title", line: `"Stand by Me"`, title: "Stand by Me"},
+		{name: "quoted fields", line: `"Earth, Wind - Fire" - "September"`, artist: "Earth, Wind - Fire", title: "September"},
+		{name: "escaped separator", line: `Artist \- Name - Song`, artist: "Artist - Name", title: "Song"},
+		{name: "escaped quote", line: `Isaac Hayes - Theme from \"Shaft\"`, artist: "Isaac Hayes", title: `Theme from "Shaft"`},
+		{name: "unbalanced quote", line: `Artist - 12" Mix`, artist: "Artist", title: `12" Mix`, warning: "unbalanced double quote"},
+		{
+			name:    "several dashes",
+			line:    "Queen - Bohemian Rhapsody - Remastered 2011",
+			artist:  "Queen",
+			title:   "Bohemian Rhapsody - Remastered 2011",
+			warning: "more than one '-' separator",
+		},
+		{name: "missing artist", line: "– Hey Jude", title: "Hey Jude"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			artist, title, warning := parseSongLine(tt.line)
+
+			assert.Equal(t, tt.artist, artist)
+			assert.Equal(t, tt.title, title)
+			if tt.warning == "" {
+				assert.Empty(t, warning)
+			} else {
+				assert.Contains(t, warning, tt.warning)
+			}
+		})
+	}
+}
+
+func TestFormatSongLine_RoundTrip(t *testing.T) {
+	tests := []struct {
+		artist string
+		title  string
+		line   string
+	}{
+		{artist: "Queen", title: "Bohemian Rhapsody", line: "Queen - Bohemian Rhapsody"},
+		{artist: "Jay-Z", title: "Stand by Me", line: "Jay-Z - Stand by Me"},
+		{artist: "Queen", title: "Bohemian Rhapsody - Remastered 2011", line: `Queen - "Bohemian Rhapsody - Remastered 2011"`},
+		{artist: "Isaac Hayes", title: `Theme from "Shaft"`, line: `Isaac Hayes - "Theme from \"Shaft\""`},
+		{artist: "Beyoncé", title: "Halo – Live", line: `Beyoncé - "Halo – Live"`},
+		{artist: "#1 Fans", title: `C:\Music`, line: `"#1 Fans" - "C:\\Music"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			line := formatSongLine(tt.artist, tt.title)
+			assert.Equal(t, tt.line, line)
+
+			artist, title, warning := parseSongLine(line)
+			assert.Equal(t, tt.artist, artist)
+			assert.Equal(t, tt.title, title)
+			assert.Empty(t, warning)
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package playlistfile
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"auto-spotify/internal/spotifyid"
+)
+
+func init() {
+	Register(Format{
+		Name:       "xspf",
+		Extensions: []string{".xspf"},
+		Reader:     XSPFReader{},
+		Writer:     XSPFWriter{},
+		Locations:  true,
+	})
+}
+
+const (
+	xspfNamespace = "http://xspf.org/ns/0/"
+	xspfVersion   = "1"
+)
+
+// xspfPlaylist is the XML layout of an XSPF playlist
+type xspfPlaylist struct {
+	XMLName    xml.Name `xml:"playlist"`
+	Xmlns      string   `xml:"xmlns,attr"`
+	Version    string   `xml:"version,attr"`
+	Title      string   `xml:"title,omitempty"`
+	Annotation string   `xml:"annotation,omitempty"`
+	Identifier string   `xml:"identifier,omitempty"`
+	TrackList  struct {
+		Tracks []xspfTrack `xml:"track"`
+	} `xml:"trackList"` // Required, even when empty
+}
+
+type xspfTrack struct {
+	Locations   []string `xml:"location"`
+	Identifiers []string `xml:"identifier"`
+	Title       string   `xml:"title,omitempty"`
+	Creator     string   `xml:"creator,omitempty"`
+	Annotation  string   `xml:"annotation,omitempty"`
+	Album       string   `xml:"album,omitempty"`
+	Duration    int64    `xml:"duration,omitempty"` // Milliseconds
+}
+
+// XSPFReader reads XSPF playlists. Songs come from each track's creator and
+// title, or from its location's file name when they're missing. Spotify
+// track URLs and URIs in a location or identifier are used as is.
+type XSPFReader struct{}
+
+// Read parses the playlist in r
+func (XSPFReader) Read(r io.Reader) (*Playlist, error) {
+	var doc xspfPlaylist
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid XSPF: %w", err)
+	}
+
+	playlist := &Playlist{
+		Name:        strings.TrimSpace(doc.Title),
+		Description: strings.TrimSpace(doc.Annotation),
+	}
+	if id, err := spotifyid.Parse("playlist", doc.Identifier); err == nil {
+		playlist.SourceID = id
+	}
+
+	for i, entry := range doc.TrackList.Tracks {
+		track := Track{
+			Artist: strings.TrimSpace(entry.Creator),
+			Title:  strings.TrimSpace(entry.Title),
+			Album:  strings.TrimSpace(entry.Album),
+			Reason: strings.TrimSpace(entry.Annotation),
+		}
+		if entry.Duration > 0 {
+			track.Duration = time.Duration(entry.Duration) * time.Millisecond
+		}
+
+		for _, ref := range append(entry.Identifiers, entry.Locations...) {
+			if uri, ok := trackURI(strings.TrimSpace(ref)); ok {
+				track.URI = uri
+				break
+			}
+		}
+
+		if track.Title == "" {
+			for _, location := range entry.Locations {
+				if path, ok := locationPath(strings.TrimSpace(location)); ok {
+					track.Artist, track.Title = songFromFileName(path)
+					break
+				}
+			}
+		}
+
+		if track.Title == "" && track.URI == "" {
+			playlist.Warnings = append(playlist.Warnings, Warning{Message: fmt.Sprintf("track %d: no song found, skipping it", i+1)})
+			continue
+		}
+		playlist.Tracks = append(playlist.Tracks, track)
+	}
+
+	return playlist, nil
+}
+
+// XSPFWriter writes XSPF playlists. Each track's location is its file in the
+// Library when it has one there, otherwise its Spotify URL.
+type XSPFWriter struct {
+	Library *Library
+}
+
+func (x XSPFWriter) withOptions(opts Options) (Writer, error) {
+	x.Library = opts.Library
+	return x, nil
+}
+
+// Write writes the playlist to w
+func (x XSPFWriter) Write(w io.Writer, playlist *Playlist) error {
+	doc := xspfPlaylist{
+		Xmlns:      xspfNamespace,
+		Version:    xspfVersion,
+		Title:      playlist.Name,
+		Annotation: playlist.Description,
+	}
+	if playlist.SourceID != "" {
+		doc.Identifier = "spotify:playlist:" + playlist.SourceID
+	}
+
+	for _, track := range playlist.Tracks {
+		entry := xspfTrack{
+			Title:      track.Title,
+			Creator:    track.Artist,
+			Annotation: track.Reason,
+			Album:      track.Album,
+			Duration:   track.Duration.Milliseconds(),
+		}
+		if location := trackLocation(x.Library, track, true); location != "" {
+			entry.Locations = []string{location}
+		}
+		if track.URI != "" {
+			entry.Identifiers = []string{track.URI}
+		}
+		doc.TrackList.Tracks = append(doc.TrackList.Tracks, entry)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
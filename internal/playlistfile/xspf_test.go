@@ -0,0 +1,105 @@
+package playlistfile
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXSPFReader(t *testing.T) {
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<playlist version="1" xmlns="http://xspf.org/ns/0/">
+  <title>Road Trip</title>
+  <annotation>Songs for the highway</annotation>
+  <trackList>
+    <track>
+      <location>file:///music/Toto/Toto%20IV/06%20Africa.mp3</location>
+      <title>Africa</title>
+      <creator>Toto</creator>
+      <album>Toto IV</album>
+      <annotation>Sing-along classic</annotation>
+      <duration>295600</duration>
+    </track>
+    <track>
+      <location>file:///music/Queen%20-%20Bohemian%20Rhapsody.flac</location>
+    </track>
+    <track>
+      <identifier>spotify:track:0DiWol3AO6WpXZgp0goxAV</identifier>
+    </track>
+    <track>
+      <location>http://radio.example.com/stream</location>
+    </track>
+  </trackList>
+</playlist>`
+
+	playlist, err := XSPFReader{}.Read(strings.NewReader(content))
+
+	require.NoError(t, err)
+	assert.Equal(t, "Road Trip", playlist.Name)
+	assert.Equal(t, "Songs for the highway", playlist.Description)
+	require.Len(t, playlist.Tracks, 3)
+	assert.Equal(t, Track{Artist: "Toto", Title: "Africa", Album: "Toto IV", Reason: "Sing-along classic", Duration: 295600 * time.Millisecond}, playlist.Tracks[0])
+	assert.Equal(t, Track{Artist: "Queen", Title: "Bohemian Rhapsody"}, playlist.Tracks[1])
+	assert.Equal(t, Track{URI: "spotify:track:0DiWol3AO6WpXZgp0goxAV"}, playlist.Tracks[2])
+
+	require.Len(t, playlist.Warnings, 1)
+	assert.Equal(t, "track 4: no song found, skipping it", playlist.Warnings[0].String())
+}
+
+func TestXSPFReader_Invalid(t *testing.T) {
+	_, err := XSPFReader{}.Read(strings.NewReader("<playlist><trackList>"))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid XSPF")
+}
+
+func TestXSPFWriter_RoundTrip(t *testing.T) {
+	playlist := &Playlist{
+		Name:        "Friday Mix",
+		Description: "Songs for the weekend",
+		SourceID:    "37i9dQZF1DXcBWIGoYBM5M",
+		Tracks: []Track{
+			{Artist: "Toto", Title: "Africa", Album: "Toto IV", Reason: "Classic", Duration: 295600 * time.Millisecond, URI: "spotify:track:2374M0fQpWi3dLnB54qaLX"},
+			{Artist: "Daft Punk", Title: "One More Time"},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, XSPFWriter{}.Write(&buf, playlist))
+
+	content := buf.String()
+	assert.True(t, strings.HasPrefix(content, `<?xml version="1.0" encoding="UTF-8"?>`))
+	assert.Contains(t, content, `<playlist xmlns="http://xspf.org/ns/0/" version="1">`)
+	assert.Contains(t, content, "<location>https://open.spotify.com/track/2374M0fQpWi3dLnB54qaLX</location>")
+	assert.Contains(t, content, "<identifier>spotify:playlist:37i9dQZF1DXcBWIGoYBM5M</identifier>")
+
+	read, err := XSPFReader{}.Read(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, playlist, read)
+}
+
+func TestXSPFWriter_EmptyTrackList(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, XSPFWriter{}.Write(&buf, &Playlist{Name: "Empty"}))
+
+	assert.Contains(t, buf.String(), "<trackList></trackList>")
+}
+
+func TestXSPFWriter_Library(t *testing.T) {
+	root := t.TempDir()
+	createLibraryFile(t, root, "Toto/Toto IV/06 Africa.mp3")
+	lib, err := OpenLibrary(root)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	writer, err := configuredWriter(ForPath("mix.xspf"), Options{Library: lib})
+	require.NoError(t, err)
+	require.NoError(t, writer.Write(&buf, &Playlist{Tracks: []Track{{Artist: "Toto", Title: "Africa"}}}))
+
+	assert.Contains(t, buf.String(), "<location>"+fileURL(filepath.Join(root, "Toto", "Toto IV", "06 Africa.mp3"))+"</location>")
+}
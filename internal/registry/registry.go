@@ -0,0 +1,210 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"auto-spotify/internal/songkey"
+	"auto-spotify/internal/statefile"
+)
+
+// Entry records the Spotify playlist generated from a source
+type Entry struct {
+	Source     string    `json:"source"`
+	PlaylistID string    `json:"playlist_id"`
+	Name       string    `json:"name"`
+	SnapshotID string    `json:"snapshot_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Registry maps the sources playlists were generated from (a file or a set
+// of prompts) to the Spotify playlists auto-spotify created for them, so
+// later runs update the same playlist even after it was renamed.
+// A nil *Registry is valid and never finds anything.
+type Registry struct {
+	path    string
+	entries map[string]Entry
+	dirty   bool
+	mu      sync.Mutex
+	now     func() time.Time
+}
+
+// DefaultPath returns the registry file location inside the user config directory
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user config directory: %w", err)
+	}
+	return filepath.Join(dir, "auto-spotify", "registry.json"), nil
+}
+
+// Open loads the registry stored at path, starting empty if the file doesn't exist yet
+func Open(path string) (*Registry, error) {
+	r := &Registry{
+		path:    path,
+		entries: make(map[string]Entry),
+		now:     time.Now,
+	}
+
+	if err := statefile.Load(path, "registry", &r.entries); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// FileSource builds the source key for a playlist loaded from a file
+func FileSource(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return "file:" + filepath.Clean(path)
+}
+
+// LibrarySource builds the source key for a playlist built from a music
+// library folder, with the filter picking its files (empty for all files)
+func LibrarySource(dir, filter string) string {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	source := "library:" + filepath.Clean(dir)
+	if filter != "" {
+		source += " | " + strings.ToLower(filter)
+	}
+	return source
+}
+
+// PlaylistSource builds the source key for a playlist restored from an export
+// of another Spotify playlist
+func PlaylistSource(playlistID string) string {
+	return "playlist:" + playlistID
+}
+
+// PromptSource builds the source key for a playlist generated from prompts
+func PromptSource(prompts []string) string {
+	normalized := make([]string, 0, len(prompts))
+	for _, prompt := range prompts {
+		normalized = append(normalized, songkey.Normalize(prompt))
+	}
+	return "prompt:" + strings.Join(normalized, " | ")
+}
+
+// Lookup returns the playlist recorded for the source, if any
+func (r *Registry) Lookup(source string) (Entry, bool) {
+	if r == nil {
+		return Entry{}, false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[source]
+	return entry, ok
+}
+
+// Record stores the playlist generated from the source
+func (r *Registry) Record(source, playlistID, name, snapshotID string) {
+	if r == nil || source == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	entry, ok := r.entries[source]
+	if !ok || entry.PlaylistID != playlistID {
+		entry = Entry{Source: source, PlaylistID: playlistID, CreatedAt: now}
+	}
+	entry.Name = name
+	entry.SnapshotID = snapshotID
+	entry.UpdatedAt = now
+
+	r.entries[source] = entry
+	r.dirty = true
+}
+
+// Forget removes every entry whose source or playlist ID matches and returns
+// how many were removed
+func (r *Registry) Forget(sourceOrID string) int {
+	if r == nil {
+		return 0
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	removed := 0
+	for source, entry := range r.entries {
+		if source == sourceOrID || entry.PlaylistID == sourceOrID {
+			delete(r.entries, source)
+			removed++
+		}
+	}
+	if removed > 0 {
+		r.dirty = true
+	}
+	return removed
+}
+
+// Entries returns all entries, most recently updated first
+func (r *Registry) Entries() []Entry {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := make([]Entry, 0, len(r.entries))
+	for _, entry := range r.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].UpdatedAt.Equal(entries[j].UpdatedAt) {
+			return entries[i].UpdatedAt.After(entries[j].UpdatedAt)
+		}
+		return entries[i].Source < entries[j].Source
+	})
+	return entries
+}
+
+// Path returns the location of the registry file
+func (r *Registry) Path() string {
+	if r == nil {
+		return ""
+	}
+	return r.path
+}
+
+// Save writes the registry to disk if it has changed since it was loaded
+func (r *Registry) Save() error {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.dirty {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(r.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode registry: %w", err)
+	}
+	if err := statefile.Write(r.path, "registry", data); err != nil {
+		return err
+	}
+
+	r.dirty = false
+	return nil
+}
@@ -0,0 +1,133 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSources(t *testing.T) {
+	assert.Equal(t, PromptSource([]string{"Chill  Indie", "rainy day"}), PromptSource([]string{"chill indie", " Rainy Day "}))
+	assert.NotEqual(t, PromptSource([]string{"chill indie"}), PromptSource([]string{"upbeat indie"}))
+	assert.Equal(t, "prompt:chill indie | rainy day", PromptSource([]string{"Chill Indie", "rainy day"}))
+	assert.Equal(t, "playlist:37i9dQZF1DXcBWIGoYBM5M", PlaylistSource("37i9dQZF1DXcBWIGoYBM5M"))
+
+	dir := t.TempDir()
+	assert.Equal(t, "file:"+filepath.Join(dir, "songs.txt"), FileSource(filepath.Join(dir, "sub", "..", "songs.txt")))
+	assert.Equal(t, "library:"+filepath.Join(dir, "Music"), LibrarySource(filepath.Join(dir, "Music", "."), ""))
+	assert.Equal(t, "library:"+filepath.Join(dir, "Music")+" | genre=rock year=1980-1989", LibrarySource(filepath.Join(dir, "Music"), "genre=Rock year=1980-1989"))
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	assert.Equal(t, "file:"+filepath.Join(wd, "songs.txt"), FileSource("songs.txt"))
+}
+
+func TestOpen_MissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "registry.json")
+
+	r, err := Open(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, path, r.Path())
+	assert.Empty(t, r.Entries())
+}
+
+func TestOpen_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry.json")
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0644))
+
+	r, err := Open(path)
+
+	assert.Nil(t, r)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse registry file")
+}
+
+func TestRegistry_RecordLookupSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "auto-spotify", "registry.json")
+	r, err := Open(path)
+	require.NoError(t, err)
+
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	r.now = func() time.Time { return created }
+	r.Record("file:/songs.txt", "playlist1", "Metal", "snap1")
+
+	// Updating keeps the creation time
+	updated := created.Add(time.Hour)
+	r.now = func() time.Time { return updated }
+	r.Record("file:/songs.txt", "playlist1", "Metal Renamed", "snap2")
+
+	require.NoError(t, r.Save())
+
+	reloaded, err := Open(path)
+	require.NoError(t, err)
+
+	entry, ok := reloaded.Lookup("file:/songs.txt")
+	require.True(t, ok)
+	assert.Equal(t, "playlist1", entry.PlaylistID)
+	assert.Equal(t, "Metal Renamed", entry.Name)
+	assert.Equal(t, "snap2", entry.SnapshotID)
+	assert.True(t, created.Equal(entry.CreatedAt))
+	assert.True(t, updated.Equal(entry.UpdatedAt))
+
+	_, ok = reloaded.Lookup("file:/other.txt")
+	assert.False(t, ok)
+}
+
+func TestRegistry_RecordNewPlaylistResetsCreation(t *testing.T) {
+	r, err := Open(filepath.Join(t.TempDir(), "registry.json"))
+	require.NoError(t, err)
+
+	first := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return first }
+	r.Record("prompt:chill", "playlist1", "Chill", "")
+
+	second := first.Add(24 * time.Hour)
+	r.now = func() time.Time { return second }
+	r.Record("prompt:chill", "playlist2", "Chill", "")
+
+	entry, ok := r.Lookup("prompt:chill")
+	require.True(t, ok)
+	assert.Equal(t, "playlist2", entry.PlaylistID)
+	assert.True(t, second.Equal(entry.CreatedAt))
+}
+
+func TestRegistry_ForgetAndEntries(t *testing.T) {
+	r, err := Open(filepath.Join(t.TempDir(), "registry.json"))
+	require.NoError(t, err)
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return base }
+	r.Record("file:/a.txt", "playlist1", "A", "")
+	r.now = func() time.Time { return base.Add(time.Hour) }
+	r.Record("file:/b.txt", "playlist2", "B", "")
+	r.Record("prompt:b", "playlist2", "B", "")
+
+	entries := r.Entries()
+	require.Len(t, entries, 3)
+	assert.Equal(t, "file:/b.txt", entries[0].Source)
+	assert.Equal(t, "file:/a.txt", entries[2].Source)
+
+	// By playlist ID, every source pointing at it goes
+	assert.Equal(t, 2, r.Forget("playlist2"))
+	assert.Equal(t, 1, r.Forget("file:/a.txt"))
+	assert.Equal(t, 0, r.Forget("file:/a.txt"))
+	assert.Empty(t, r.Entries())
+}
+
+func TestRegistry_Nil(t *testing.T) {
+	var r *Registry
+
+	r.Record("file:/a.txt", "playlist1", "A", "")
+	_, ok := r.Lookup("file:/a.txt")
+
+	assert.False(t, ok)
+	assert.Equal(t, 0, r.Forget("file:/a.txt"))
+	assert.Nil(t, r.Entries())
+	assert.Equal(t, "", r.Path())
+	assert.NoError(t, r.Save())
+}
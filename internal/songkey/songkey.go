@@ -0,0 +1,56 @@
+// Package songkey normalizes song artists and titles, so the same song is
+// recognized across Spotify releases, playlist files and music file tags.
+package songkey
+
+import (
+	"regexp"
+	"strings"
+)
+
+// versionPattern matches the words that mark a suffix like " - Remastered 2011"
+// or " (Live)" as version info rather than part of the title, like " (Part 1)"
+var versionPattern = regexp.MustCompile(`\b(remaster(ed)?|live|remix(ed)?|edit|version|feat|ft|featuring|mono|stereo)\b`)
+
+// Key builds a normalized artist/title key so the same song released on
+// different albums (single, remaster, compilation...) is treated as one
+func Key(artist, title string) string {
+	title = strings.ToLower(strings.TrimSpace(title))
+	for {
+		trimmed := trimVersionSuffix(title)
+		if trimmed == title {
+			break
+		}
+		title = trimmed
+	}
+
+	return Exact(artist, title)
+}
+
+// trimVersionSuffix removes the last " - ...", "(...)" or "[...]" suffix of a
+// lowercase title when it's version info. A title is never trimmed to nothing.
+func trimVersionSuffix(title string) string {
+	var cut int
+	switch {
+	case strings.HasSuffix(title, ")"):
+		cut = strings.LastIndex(title, "(")
+	case strings.HasSuffix(title, "]"):
+		cut = strings.LastIndex(title, "[")
+	default:
+		cut = strings.LastIndex(title, " - ")
+	}
+	if cut <= 0 || !versionPattern.MatchString(title[cut:]) {
+		return title
+	}
+	return strings.TrimSpace(title[:cut])
+}
+
+// Exact builds a normalized artist/title key that keeps version suffixes, for
+// lookups where a live version and the studio recording must stay apart
+func Exact(artist, title string) string {
+	return Normalize(artist) + "|" + Normalize(title)
+}
+
+// Normalize lowercases a name or prompt and collapses its whitespace
+func Normalize(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
@@ -0,0 +1,118 @@
+package songkey
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		artistA string
+		titleA  string
+		artistB string
+		titleB  string
+		same    bool
+	}{
+		{
+			name:    "case and spacing",
+			artistA: "The Beatles",
+			titleA:  "Hey Jude",
+			artistB: "the  beatles",
+			titleB:  " HEY JUDE ",
+			same:    true,
+		},
+		{
+			name:    "remastered suffix",
+			artistA: "Queen",
+			titleA:  "Bohemian Rhapsody",
+			artistB: "Queen",
+			titleB:  "Bohemian Rhapsody - Remastered 2011",
+			same:    true,
+		},
+		{
+			name:    "parenthetical version",
+			artistA: "Metallica",
+			titleA:  "One",
+			artistB: "Metallica",
+			titleB:  "One (Remastered) [Live]",
+			same:    true,
+		},
+		{
+			name:    "featured artist",
+			artistA: "Daft Punk",
+			titleA:  "Get Lucky (feat. Pharrell Williams) - Radio Edit",
+			artistB: "Daft Punk",
+			titleB:  "Get Lucky",
+			same:    true,
+		},
+		{
+			name:    "parts are different songs",
+			artistA: "Dire Straits",
+			titleA:  "Telegraph Road (Part 1)",
+			artistB: "Dire Straits",
+			titleB:  "Telegraph Road (Part 2)",
+			same:    false,
+		},
+		{
+			name:    "abbreviated parts are different songs",
+			artistA: "Pink Floyd",
+			titleA:  "Another Brick in the Wall - Pt. 1",
+			artistB: "Pink Floyd",
+			titleB:  "Another Brick in the Wall - Pt. 2",
+			same:    false,
+		},
+		{
+			name:    "version of a part",
+			artistA: "Pink Floyd",
+			titleA:  "Another Brick in the Wall, Pt. 2 - Remastered 2011",
+			artistB: "Pink Floyd",
+			titleB:  "Another Brick in the Wall, Pt. 2",
+			same:    true,
+		},
+		{
+			name:    "bracketed part",
+			artistA: "Kanye West",
+			titleA:  "Father Stretch My Hands [Pt. 1]",
+			artistB: "Kanye West",
+			titleB:  "Father Stretch My Hands [Pt. 2]",
+			same:    false,
+		},
+		{
+			name:    "leading parenthetical is kept",
+			artistA: "The Rolling Stones",
+			titleA:  "(I Can't Get No) Satisfaction",
+			artistB: "The Rolling Stones",
+			titleB:  "Satisfaction",
+			same:    false,
+		},
+		{
+			name:    "different artists",
+			artistA: "Johnny Cash",
+			titleA:  "Hurt",
+			artistB: "Nine Inch Nails",
+			titleB:  "Hurt",
+			same:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := Key(tt.artistA, tt.titleA)
+			b := Key(tt.artistB, tt.titleB)
+			if tt.same {
+				assert.Equal(t, a, b)
+			} else {
+				assert.NotEqual(t, a, b)
+			}
+		})
+	}
+}
+
+func TestExact(t *testing.T) {
+	assert.Equal(t, "metallica|one", Exact("Metallica", "One"))
+	assert.Equal(t, Exact("the beatles", "hey jude"), Exact("  The   Beatles ", "Hey  Jude"))
+	assert.NotEqual(t, Exact("Queen", "Bohemian Rhapsody"), Exact("Queen", "Under Pressure"))
+	assert.NotEqual(t, Exact("Queen", "Bohemian Rhapsody"), Exact("Queen", "Bohemian Rhapsody (Live)"))
+}
@@ -0,0 +1,121 @@
+package spotify
+
+import (
+	"context"
+	"fmt"
+
+	"auto-spotify/internal/songkey"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// primaryArtist returns the name of the track's first artist
+func primaryArtist(track *spotify.FullTrack) string {
+	if len(track.Artists) > 0 {
+		return track.Artists[0].Name
+	}
+	return ""
+}
+
+// duplicateTracker remembers which tracks have been seen by ID and by song
+type duplicateTracker struct {
+	ids   map[string]bool
+	songs map[string]bool
+}
+
+func newDuplicateTracker() *duplicateTracker {
+	return &duplicateTracker{
+		ids:   make(map[string]bool),
+		songs: make(map[string]bool),
+	}
+}
+
+// add records tracks that are already taken without checking them
+func (d *duplicateTracker) add(tracks []TrackInfo) {
+	for _, track := range tracks {
+		d.seen(track.ID, track.Artist, track.Title)
+	}
+}
+
+// seen records the track and reports whether it was already recorded
+func (d *duplicateTracker) seen(id, artist, title string) bool {
+	key := songkey.Key(artist, title)
+	if d.ids[id] || d.songs[key] {
+		return true
+	}
+	d.ids[id] = true
+	d.songs[key] = true
+	return false
+}
+
+// FindDuplicates returns every track that repeats an earlier track, either by
+// ID or by normalized artist/title. The first occurrence is kept.
+func FindDuplicates(tracks []TrackInfo) []TrackInfo {
+	tracker := newDuplicateTracker()
+
+	var duplicates []TrackInfo
+	for _, track := range tracks {
+		if tracker.seen(track.ID, track.Artist, track.Title) {
+			duplicates = append(duplicates, track)
+		}
+	}
+
+	return duplicates
+}
+
+// DedupePlaylist removes duplicate tracks from a playlist, keeping the first
+// occurrence of each song. With dryRun the duplicates are only reported.
+func (s *Service) DedupePlaylist(ctx context.Context, playlistID string, dryRun bool) ([]TrackInfo, error) {
+	if s.client == nil {
+		return nil, fmt.Errorf("not authenticated with Spotify")
+	}
+
+	// Pin removals to the snapshot the positions were read from
+	playlist, err := s.client.GetPlaylist(ctx, spotify.ID(playlistID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get playlist: %w", err)
+	}
+
+	tracks, err := s.GetPlaylistTracks(ctx, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicates := FindDuplicates(tracks)
+	if dryRun || len(duplicates) == 0 {
+		return duplicates, nil
+	}
+
+	s.saveVersion(playlist, tracks, "dedupe")
+
+	// Group positions by track so each occurrence is removed individually
+	var order []string
+	positions := make(map[string][]int)
+	for _, track := range duplicates {
+		if _, ok := positions[track.ID]; !ok {
+			order = append(order, track.ID)
+		}
+		positions[track.ID] = append(positions[track.ID], track.Position)
+	}
+
+	var toRemove []spotify.TrackToRemove
+	for _, id := range order {
+		toRemove = append(toRemove, spotify.NewTrackToRemove(id, positions[id]))
+	}
+
+	// Remove tracks in batches (Spotify API limit)
+	const batchSize = 100
+	for i := 0; i < len(toRemove); i += batchSize {
+		end := i + batchSize
+		if end > len(toRemove) {
+			end = len(toRemove)
+		}
+
+		_, err := s.client.RemoveTracksFromPlaylistOpt(ctx, playlist.ID, toRemove[i:end], playlist.SnapshotID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove duplicate tracks: %w", err)
+		}
+	}
+
+	return duplicates, nil
+}
@@ -0,0 +1,41 @@
+package spotify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	tracks := []TrackInfo{
+		{ID: "1", Artist: "Metallica", Title: "One", Position: 0},
+		{ID: "2", Artist: "Metallica", Title: "Enter Sandman", Position: 1},
+		{ID: "1", Artist: "Metallica", Title: "One", Position: 2},
+		{ID: "3", Artist: "Metallica", Title: "Enter Sandman - Remastered 2021", Position: 3},
+		{ID: "4", Artist: "Iron Maiden", Title: "The Trooper", Position: 4},
+	}
+
+	duplicates := FindDuplicates(tracks)
+
+	assert.Len(t, duplicates, 2)
+	assert.Equal(t, 2, duplicates[0].Position)
+	assert.Equal(t, 3, duplicates[1].Position)
+}
+
+func TestFindDuplicates_None(t *testing.T) {
+	tracks := []TrackInfo{
+		{ID: "1", Artist: "Metallica", Title: "One"},
+		{ID: "2", Artist: "Iron Maiden", Title: "The Trooper"},
+	}
+
+	assert.Empty(t, FindDuplicates(tracks))
+}
+
+func TestDuplicateTracker_Add(t *testing.T) {
+	tracker := newDuplicateTracker()
+	tracker.add([]TrackInfo{{ID: "1", Artist: "Metallica", Title: "One"}})
+
+	assert.True(t, tracker.seen("1", "Someone", "Else"))
+	assert.True(t, tracker.seen("2", "Metallica", "One - Remastered"))
+	assert.False(t, tracker.seen("3", "Metallica", "Battery"))
+}
@@ -0,0 +1,162 @@
+package spotify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"auto-spotify/internal/visibility"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// Visibility controls who can see and edit a playlist
+type Visibility = visibility.Visibility
+
+const (
+	VisibilityPrivate       = visibility.Private
+	VisibilityPublic        = visibility.Public
+	VisibilityCollaborative = visibility.Collaborative
+)
+
+// defaultAPIURL is the base URL of the Spotify Web API
+const defaultAPIURL = "https://api.spotify.com/v1/"
+
+// ParseVisibility parses "private", "public" or "collaborative". An empty
+// string means no visibility was chosen.
+func ParseVisibility(value string) (Visibility, error) {
+	return visibility.Parse(value)
+}
+
+// SetVisibility sets the visibility of created playlists. Existing playlists
+// are changed to it when updated; when empty, new playlists are private and
+// existing ones keep their visibility.
+func (s *Service) SetVisibility(visibility Visibility) {
+	s.visibility = visibility
+}
+
+// Visibility returns the visibility set with SetVisibility
+func (s *Service) Visibility() Visibility {
+	return s.visibility
+}
+
+// playlistDetails holds the playlist fields to change; nil fields are left alone
+type playlistDetails struct {
+	Name          *string `json:"name,omitempty"`
+	Description   *string `json:"description,omitempty"`
+	Public        *bool   `json:"public,omitempty"`
+	Collaborative *bool   `json:"collaborative,omitempty"`
+}
+
+func (d playlistDetails) empty() bool {
+	return d.Name == nil && d.Description == nil && d.Public == nil && d.Collaborative == nil
+}
+
+// detailChanges works out which fields of the playlist differ from the wanted
+// name, description and visibility. An empty description or visibility is
+// left as it is.
+func detailChanges(playlist *spotify.FullPlaylist, name, description string, visibility Visibility) playlistDetails {
+	var changes playlistDetails
+
+	if name != "" && name != playlist.Name {
+		changes.Name = &name
+	}
+	if description != "" && description != playlist.Description {
+		changes.Description = &description
+	}
+	if visibility != "" {
+		public := visibility == VisibilityPublic
+		collaborative := visibility == VisibilityCollaborative
+		if public != playlist.IsPublic {
+			changes.Public = &public
+		}
+		if collaborative != playlist.Collaborative {
+			changes.Collaborative = &collaborative
+		}
+	}
+
+	return changes
+}
+
+// syncPlaylistDetails changes the playlist's name, description and visibility
+// to the wanted ones and updates playlist to match
+func (s *Service) syncPlaylistDetails(ctx context.Context, playlist *spotify.FullPlaylist, name, description string, visibility Visibility) error {
+	changes := detailChanges(playlist, name, description, visibility)
+	if changes.empty() {
+		return nil
+	}
+
+	if err := s.changePlaylistDetails(ctx, playlist, changes); err != nil {
+		return err
+	}
+
+	if changes.Name != nil {
+		playlist.Name = *changes.Name
+	}
+	if changes.Description != nil {
+		playlist.Description = *changes.Description
+	}
+	if changes.Public != nil {
+		playlist.IsPublic = *changes.Public
+	}
+	if changes.Collaborative != nil {
+		playlist.Collaborative = *changes.Collaborative
+	}
+	return nil
+}
+
+// changePlaylistDetails sends the changes to the Spotify API. The client
+// library has no collaborative field, so changes to it are sent directly.
+func (s *Service) changePlaylistDetails(ctx context.Context, playlist *spotify.FullPlaylist, changes playlistDetails) error {
+	if s.client == nil || s.httpClient == nil {
+		return fmt.Errorf("not authenticated with Spotify")
+	}
+
+	if changes.Collaborative == nil {
+		// Empty names and descriptions are left out of the request, the
+		// visibility is sent as it is when it doesn't change
+		var name, description string
+		if changes.Name != nil {
+			name = *changes.Name
+		}
+		if changes.Description != nil {
+			description = *changes.Description
+		}
+		public := playlist.IsPublic
+		if changes.Public != nil {
+			public = *changes.Public
+		}
+		if err := s.client.ChangePlaylistNameAccessAndDescription(ctx, playlist.ID, name, description, public); err != nil {
+			return fmt.Errorf("failed to change playlist details: %w", err)
+		}
+		return nil
+	}
+
+	body, err := json.Marshal(changes)
+	if err != nil {
+		return fmt.Errorf("failed to encode playlist details: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.apiURL+"playlists/"+string(playlist.ID), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build playlist details request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to change playlist details: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to change playlist details: %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	return nil
+}
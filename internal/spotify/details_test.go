@@ -0,0 +1,133 @@
+package spotify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmb3/spotify/v2"
+)
+
+func testPlaylist(name, description string, public, collaborative bool) *spotify.FullPlaylist {
+	playlist := &spotify.FullPlaylist{}
+	playlist.ID = "37i9dQZF1DXcBWIGoYBM5M"
+	playlist.Name = name
+	playlist.Description = description
+	playlist.IsPublic = public
+	playlist.Collaborative = collaborative
+	return playlist
+}
+
+func TestDetailChanges(t *testing.T) {
+	playlist := testPlaylist("Road Trip", "Old description", true, false)
+
+	// Nothing to change
+	assert.True(t, detailChanges(playlist, "Road Trip", "Old description", VisibilityPublic).empty())
+	assert.True(t, detailChanges(playlist, "", "", "").empty())
+
+	changes := detailChanges(playlist, "Road Trip 2", "New description", VisibilityCollaborative)
+	require.NotNil(t, changes.Name)
+	assert.Equal(t, "Road Trip 2", *changes.Name)
+	require.NotNil(t, changes.Description)
+	assert.Equal(t, "New description", *changes.Description)
+	require.NotNil(t, changes.Public)
+	assert.False(t, *changes.Public)
+	require.NotNil(t, changes.Collaborative)
+	assert.True(t, *changes.Collaborative)
+
+	// Only the visibility differs
+	changes = detailChanges(playlist, "Road Trip", "", VisibilityPrivate)
+	assert.Nil(t, changes.Name)
+	assert.Nil(t, changes.Description)
+	require.NotNil(t, changes.Public)
+	assert.False(t, *changes.Public)
+	assert.Nil(t, changes.Collaborative)
+}
+
+func TestSyncPlaylistDetails(t *testing.T) {
+	var method, path string
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		path = r.URL.Path
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &body)
+	}))
+	defer server.Close()
+
+	service := testDetailsService(server)
+
+	playlist := testPlaylist("Road Trip", "Old description", true, false)
+	err := service.syncPlaylistDetails(context.Background(), playlist, "Road Trip", "New description", VisibilityPrivate)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPut, method)
+	assert.Equal(t, "/v1/playlists/37i9dQZF1DXcBWIGoYBM5M", path)
+	assert.Equal(t, map[string]interface{}{"description": "New description", "public": false}, body)
+	assert.Equal(t, "New description", playlist.Description)
+	assert.False(t, playlist.IsPublic)
+}
+
+func TestSyncPlaylistDetails_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"status":403,"message":"Forbidden"}}`, http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	service := testDetailsService(server)
+
+	playlist := testPlaylist("Road Trip", "", false, false)
+	err := service.syncPlaylistDetails(context.Background(), playlist, "Renamed", "", "")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Forbidden")
+	assert.Equal(t, "Road Trip", playlist.Name)
+}
+
+func TestSyncPlaylistDetails_Collaborative(t *testing.T) {
+	var path string
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &body)
+	}))
+	defer server.Close()
+
+	service := testDetailsService(server)
+
+	playlist := testPlaylist("Road Trip", "", true, false)
+	err := service.syncPlaylistDetails(context.Background(), playlist, "", "", VisibilityCollaborative)
+
+	require.NoError(t, err)
+	assert.Equal(t, "/v1/playlists/37i9dQZF1DXcBWIGoYBM5M", path)
+	assert.Equal(t, map[string]interface{}{"public": false, "collaborative": true}, body)
+	assert.True(t, playlist.Collaborative)
+	assert.False(t, playlist.IsPublic)
+}
+
+// testDetailsService returns a service whose client library and direct
+// requests go to server
+func testDetailsService(server *httptest.Server) *Service {
+	service := NewService("id", "secret", "http://localhost:8080/callback")
+	service.httpClient = server.Client()
+	service.apiURL = server.URL + "/v1/"
+	service.client = spotify.New(server.Client(), spotify.WithBaseURL(service.apiURL))
+	return service
+}
+
+func TestSyncPlaylistDetails_NotAuthenticated(t *testing.T) {
+	service := NewService("id", "secret", "http://localhost:8080/callback")
+
+	// No changes means no request
+	playlist := testPlaylist("Road Trip", "", false, false)
+	assert.NoError(t, service.syncPlaylistDetails(context.Background(), playlist, "Road Trip", "", ""))
+
+	err := service.syncPlaylistDetails(context.Background(), playlist, "Renamed", "", "")
+	assert.Error(t, err)
+}
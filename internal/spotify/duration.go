@@ -0,0 +1,106 @@
+package spotify
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"auto-spotify/internal/openai"
+)
+
+// DefaultDurationTolerance is how far a duration-targeted playlist may be from its target
+const DefaultDurationTolerance = 2 * time.Minute
+
+// maxTopUpRounds limits how often a short playlist asks for more songs
+const maxTopUpRounds = 3
+
+// fitToDuration trims the resolved tracks to the target runtime and, when the
+// playlist comes up short, asks opts.TopUp for more songs
+func (s *Service) fitToDuration(ctx context.Context, results []SearchResult, duplicates *duplicateTracker, excluded *exclusionMatcher, opts PlaylistOptions) []SearchResult {
+	tolerance := opts.DurationTolerance
+	if tolerance <= 0 {
+		tolerance = DefaultDurationTolerance
+	}
+
+	total := selectForDuration(results, opts.TargetDuration, tolerance)
+
+	for round := 0; total < opts.TargetDuration-tolerance && opts.TopUp != nil && round < maxTopUpRounds; round++ {
+		missing := opts.TargetDuration - total
+		fmt.Fprintf(s.progress, "⏱️  Playlist is %s short of %s, asking for more songs...\n", FormatDuration(missing), FormatDuration(opts.TargetDuration))
+
+		var have []openai.Song
+		for _, result := range results {
+			have = append(have, result.Song)
+		}
+
+		more, err := opts.TopUp(ctx, have, missing)
+		if err != nil {
+			s.logger.Warn("failed to get more songs", "error", err)
+			break
+		}
+		if len(more) == 0 {
+			break
+		}
+
+		fmt.Fprintf(s.progress, "🔍 Searching for %d more songs...\n", len(more))
+		results = append(results, s.resolveSongs(ctx, more, duplicates, excluded)...)
+		total = selectForDuration(results, opts.TargetDuration, tolerance)
+	}
+
+	trimmed := 0
+	for _, result := range results {
+		if result.Trimmed {
+			trimmed++
+		}
+	}
+	if trimmed > 0 {
+		fmt.Fprintf(s.progress, "✂️  Left out %d songs to stay within %s of %s\n", trimmed, FormatDuration(tolerance), FormatDuration(opts.TargetDuration))
+	}
+
+	return results
+}
+
+// selectForDuration walks the found tracks in order, keeping tracks while they
+// fit under target+tolerance and stopping once the total is within tolerance
+// of the target. Tracks left out are marked Trimmed. It returns the total
+// runtime of the kept tracks.
+func selectForDuration(results []SearchResult, target, tolerance time.Duration) time.Duration {
+	var total time.Duration
+
+	for i := range results {
+		result := &results[i]
+		result.Trimmed = false
+		if !result.Found || result.Duplicate || result.Excluded {
+			continue
+		}
+
+		length := result.Track.TimeDuration()
+		if total >= target-tolerance || total+length > target+tolerance {
+			result.Trimmed = true
+			continue
+		}
+		total += length
+	}
+
+	return total
+}
+
+// TotalDuration returns the combined runtime of the tracks added to the playlist
+func TotalDuration(results []SearchResult) time.Duration {
+	var total time.Duration
+	for _, result := range results {
+		if result.Added() {
+			total += result.Track.TimeDuration()
+		}
+	}
+	return total
+}
+
+// FormatDuration formats a runtime as "1h05m" or "45m12s"
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d >= time.Hour {
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
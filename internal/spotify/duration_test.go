@@ -0,0 +1,104 @@
+package spotify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"auto-spotify/internal/openai"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zmb3/spotify/v2"
+)
+
+func TestSelectForDuration_Trims(t *testing.T) {
+	results := []SearchResult{
+		foundResult("1", 4*time.Minute),
+		foundResult("2", 4*time.Minute),
+		foundResult("3", 4*time.Minute),
+		foundResult("4", 4*time.Minute),
+	}
+
+	total := selectForDuration(results, 10*time.Minute, 2*time.Minute)
+
+	assert.Equal(t, 8*time.Minute, total)
+	assert.False(t, results[0].Trimmed)
+	assert.False(t, results[1].Trimmed)
+	assert.True(t, results[2].Trimmed)
+	assert.True(t, results[3].Trimmed)
+}
+
+func TestSelectForDuration_SkipsTracksThatOvershoot(t *testing.T) {
+	results := []SearchResult{
+		foundResult("1", 5*time.Minute),
+		foundResult("2", 9*time.Minute),
+		foundResult("3", 4*time.Minute),
+		{Song: openai.Song{Title: "missing"}},
+	}
+
+	total := selectForDuration(results, 10*time.Minute, time.Minute)
+
+	assert.Equal(t, 9*time.Minute, total)
+	assert.False(t, results[0].Trimmed)
+	assert.True(t, results[1].Trimmed)
+	assert.False(t, results[2].Trimmed)
+	assert.False(t, results[3].Trimmed)
+}
+
+func TestSelectForDuration_IgnoresDuplicates(t *testing.T) {
+	duplicate := foundResult("1", 4*time.Minute)
+	duplicate.Duplicate = true
+
+	results := []SearchResult{
+		foundResult("1", 4*time.Minute),
+		duplicate,
+	}
+
+	total := selectForDuration(results, 30*time.Minute, 2*time.Minute)
+
+	assert.Equal(t, 4*time.Minute, total)
+	assert.Equal(t, 4*time.Minute, TotalDuration(results))
+}
+
+func TestFitToDuration_TopUp(t *testing.T) {
+	service := NewService("test", "test", "http://localhost:8080/callback")
+
+	var gotMissing time.Duration
+	var gotHave []openai.Song
+	opts := PlaylistOptions{
+		TargetDuration: 20 * time.Minute,
+		TopUp: func(ctx context.Context, have []openai.Song, missing time.Duration) ([]openai.Song, error) {
+			gotHave = have
+			gotMissing = missing
+			// No more songs available
+			return nil, nil
+		},
+	}
+
+	results := []SearchResult{foundResult("1", 5*time.Minute)}
+	results = service.fitToDuration(context.Background(), results, newDuplicateTracker(), nil, opts)
+
+	assert.Len(t, results, 1)
+	assert.Len(t, gotHave, 1)
+	assert.Equal(t, 15*time.Minute, gotMissing)
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "45m00s", FormatDuration(45*time.Minute))
+	assert.Equal(t, "3m25s", FormatDuration(3*time.Minute+25*time.Second+300*time.Millisecond))
+	assert.Equal(t, "1h05m", FormatDuration(65*time.Minute))
+}
+
+func foundResult(id string, length time.Duration) SearchResult {
+	return SearchResult{
+		Song: openai.Song{Artist: "Artist " + id, Title: "Song " + id},
+		Track: &spotify.FullTrack{
+			SimpleTrack: spotify.SimpleTrack{
+				ID:       spotify.ID(id),
+				Name:     "Song " + id,
+				Duration: int(length.Milliseconds()),
+			},
+		},
+		Found: true,
+	}
+}
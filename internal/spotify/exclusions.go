@@ -0,0 +1,94 @@
+package spotify
+
+import (
+	"context"
+	"fmt"
+
+	"auto-spotify/internal/songkey"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// Exclusions lists artists and tracks that must never be added to a playlist,
+// however the AI or the search resolved them
+type Exclusions struct {
+	Artists []string    // Tracks featuring any of these artists are excluded
+	Tracks  []TrackInfo // Excluded by ID and by normalized artist/title
+}
+
+// Empty reports whether nothing is excluded
+func (e Exclusions) Empty() bool {
+	return len(e.Artists) == 0 && len(e.Tracks) == 0
+}
+
+// exclusionMatcher checks resolved tracks against the exclusions
+type exclusionMatcher struct {
+	artists map[string]bool
+	ids     map[string]bool
+	songs   map[string]bool
+}
+
+func newExclusionMatcher(e Exclusions) *exclusionMatcher {
+	m := &exclusionMatcher{
+		artists: make(map[string]bool),
+		ids:     make(map[string]bool),
+		songs:   make(map[string]bool),
+	}
+	for _, artist := range e.Artists {
+		m.artists[songkey.Normalize(artist)] = true
+	}
+	for _, track := range e.Tracks {
+		m.ids[track.ID] = true
+		m.songs[songkey.Key(track.Artist, track.Title)] = true
+	}
+	return m
+}
+
+// excludes reports whether the track is excluded. A nil matcher excludes nothing.
+func (m *exclusionMatcher) excludes(track *spotify.FullTrack) bool {
+	if m == nil {
+		return false
+	}
+
+	if m.ids[string(track.ID)] || (track.LinkedFrom != nil && m.ids[string(track.LinkedFrom.ID)]) {
+		return true
+	}
+	for _, artist := range track.Artists {
+		if m.artists[songkey.Normalize(artist.Name)] {
+			return true
+		}
+	}
+	return m.songs[songkey.Key(primaryArtist(track), track.Name)]
+}
+
+// GetTracks retrieves the tracks with the given IDs
+func (s *Service) GetTracks(ctx context.Context, ids []spotify.ID) ([]TrackInfo, error) {
+	if s.client == nil {
+		return nil, fmt.Errorf("not authenticated with Spotify")
+	}
+
+	var allTracks []TrackInfo
+
+	// Spotify API has a limit of 50 tracks per request
+	const batchSize = 50
+	for i := 0; i < len(ids); i += batchSize {
+		end := i + batchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		tracks, err := s.client.GetTracks(ctx, ids[i:end], s.marketOptions(ctx)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tracks: %w", err)
+		}
+
+		for j, track := range tracks {
+			if track == nil {
+				return nil, fmt.Errorf("track %s not found", ids[i+j])
+			}
+			allTracks = append(allTracks, newTrackInfo(track, i+j))
+		}
+	}
+
+	return allTracks, nil
+}
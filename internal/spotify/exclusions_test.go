@@ -0,0 +1,59 @@
+package spotify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zmb3/spotify/v2"
+)
+
+func TestExclusionMatcher(t *testing.T) {
+	matcher := newExclusionMatcher(Exclusions{
+		Artists: []string{"nickelback"},
+		Tracks: []TrackInfo{
+			{ID: "4uLU6hMCjMI75M1A2tKUQC", Artist: "Rick Astley", Title: "Never Gonna Give You Up"},
+			{ID: "7ouMYWpwJ422jRcDASZB7P", Artist: "Toto", Title: "Africa"},
+		},
+	})
+
+	track := func(id, title string, artists ...string) *spotify.FullTrack {
+		t := &spotify.FullTrack{}
+		t.ID = spotify.ID(id)
+		t.Name = title
+		for _, artist := range artists {
+			t.Artists = append(t.Artists, spotify.SimpleArtist{Name: artist})
+		}
+		return t
+	}
+
+	tests := []struct {
+		name     string
+		track    *spotify.FullTrack
+		excluded bool
+	}{
+		{name: "excluded artist", track: track("a", "Photograph", "Nickelback"), excluded: true},
+		{name: "excluded featured artist", track: track("b", "Collab", "Someone", "NICKELBACK"), excluded: true},
+		{name: "excluded track ID", track: track("4uLU6hMCjMI75M1A2tKUQC", "Whatever", "Anyone"), excluded: true},
+		{name: "same song on another album", track: track("c", "Africa - Remastered", "Toto"), excluded: true},
+		{name: "other song", track: track("d", "Rosanna", "Toto"), excluded: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.excluded, matcher.excludes(tt.track))
+		})
+	}
+
+	relinked := track("e", "Something Else", "Anyone")
+	relinked.LinkedFrom = &spotify.LinkedFromInfo{ID: "7ouMYWpwJ422jRcDASZB7P"}
+	assert.True(t, matcher.excludes(relinked))
+
+	var none *exclusionMatcher
+	assert.False(t, none.excludes(track("a", "Photograph", "Nickelback")))
+}
+
+func TestExclusions_Empty(t *testing.T) {
+	assert.True(t, Exclusions{}.Empty())
+	assert.False(t, Exclusions{Artists: []string{"Nickelback"}}.Empty())
+	assert.False(t, Exclusions{Tracks: []TrackInfo{{ID: "x"}}}.Empty())
+}
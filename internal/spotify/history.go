@@ -0,0 +1,91 @@
+package spotify
+
+import (
+	"context"
+	"fmt"
+
+	"auto-spotify/internal/history"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// SetHistory sets where earlier versions of playlists are saved before changes
+func (s *Service) SetHistory(h *history.History) {
+	s.history = h
+}
+
+// History returns the playlist history in use, if any
+func (s *Service) History() *history.History {
+	return s.history
+}
+
+// saveVersion records the playlist's tracks before action changes them
+func (s *Service) saveVersion(playlist *spotify.FullPlaylist, tracks []TrackInfo, action string) {
+	if s.history == nil {
+		return
+	}
+
+	saved := make([]history.Track, 0, len(tracks))
+	for _, track := range tracks {
+		saved = append(saved, history.Track{ID: track.ID, Artist: track.Artist, Title: track.Title})
+	}
+
+	version := s.history.Add(string(playlist.ID), history.Version{
+		Action:     action,
+		Name:       playlist.Name,
+		SnapshotID: playlist.SnapshotID,
+		Tracks:     saved,
+	})
+	if err := s.history.Save(); err != nil {
+		s.logger.Warn("failed to save playlist history", "path", s.history.Path(), "error", err)
+		return
+	}
+	fmt.Fprintf(s.progress, "   💾 Saved the previous version as #%d\n", version.Number)
+}
+
+// RestorePlaylist replaces the playlist's tracks (and name) with a saved
+// version. The current tracks are saved first, so the restore can be undone
+// too. Local files can't be added through the API and are not restored.
+func (s *Service) RestorePlaylist(ctx context.Context, playlistID string, version history.Version) error {
+	if s.client == nil {
+		return fmt.Errorf("not authenticated with Spotify")
+	}
+
+	playlist, err := s.client.GetPlaylist(ctx, spotify.ID(playlistID))
+	if err != nil {
+		return fmt.Errorf("failed to get playlist: %w", err)
+	}
+
+	if s.history != nil {
+		current, err := s.GetPlaylistTracks(ctx, playlistID)
+		if err != nil {
+			return fmt.Errorf("failed to read current tracks: %w", err)
+		}
+		s.saveVersion(playlist, current, "undo")
+	}
+
+	var trackIDs []spotify.ID
+	for _, track := range version.Tracks {
+		if track.ID != "" {
+			trackIDs = append(trackIDs, spotify.ID(track.ID))
+		}
+	}
+
+	// Replacing takes at most 100 tracks, the rest are appended
+	first := trackIDs
+	if len(first) > 100 {
+		first = first[:100]
+	}
+	if err := s.client.ReplacePlaylistTracks(ctx, playlist.ID, first...); err != nil {
+		return fmt.Errorf("failed to replace playlist tracks: %w", err)
+	}
+	if _, err := s.addTracks(ctx, playlist.ID, trackIDs[len(first):], 0, 0); err != nil {
+		return err
+	}
+
+	if err := s.syncPlaylistDetails(ctx, playlist, version.Name, "", ""); err != nil {
+		return err
+	}
+
+	return nil
+}
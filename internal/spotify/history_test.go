@@ -0,0 +1,55 @@
+package spotify
+
+import (
+	"path/filepath"
+	"testing"
+
+	"auto-spotify/internal/history"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmb3/spotify/v2"
+)
+
+func TestSaveVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	playlistHistory, err := history.Open(path)
+	require.NoError(t, err)
+
+	service := NewService("id", "secret", "http://localhost:8080/callback")
+	service.SetHistory(playlistHistory)
+
+	playlist := &spotify.FullPlaylist{}
+	playlist.ID = "playlist1"
+	playlist.Name = "My Mix"
+	playlist.SnapshotID = "snap1"
+
+	service.saveVersion(playlist, []TrackInfo{
+		{ID: "track1", Artist: "Metallica", Title: "One", Album: "...And Justice for All"},
+		{ID: "track2", Artist: "Toto", Title: "Africa"},
+	}, "update")
+
+	// Saved to disk right away
+	reloaded, err := history.Open(path)
+	require.NoError(t, err)
+	version, ok := reloaded.Version("playlist1", 1)
+	require.True(t, ok)
+	assert.Equal(t, "update", version.Action)
+	assert.Equal(t, "My Mix", version.Name)
+	assert.Equal(t, "snap1", version.SnapshotID)
+	assert.Equal(t, []history.Track{
+		{ID: "track1", Artist: "Metallica", Title: "One"},
+		{ID: "track2", Artist: "Toto", Title: "Africa"},
+	}, version.Tracks)
+}
+
+func TestSaveVersion_Disabled(t *testing.T) {
+	service := NewService("id", "secret", "http://localhost:8080/callback")
+
+	playlist := &spotify.FullPlaylist{}
+	playlist.ID = "playlist1"
+
+	// No history configured, nothing to do
+	service.saveVersion(playlist, []TrackInfo{{ID: "track1"}}, "update")
+	assert.Nil(t, service.History())
+}
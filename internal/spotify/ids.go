@@ -0,0 +1,33 @@
+package spotify
+
+import (
+	"auto-spotify/internal/spotifyid"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// ParseID extracts a Spotify ID of the given kind ("track", "playlist", ...) from a
+// URI (spotify:track:ID), an open.spotify.com URL or a bare ID
+func ParseID(kind, input string) (spotify.ID, error) {
+	id, err := spotifyid.Parse(kind, input)
+	if err != nil {
+		return "", err
+	}
+	return spotify.ID(id), nil
+}
+
+// ParseTrackID extracts a track ID from a Spotify track URI, URL or bare ID
+func ParseTrackID(input string) (spotify.ID, error) {
+	return ParseID("track", input)
+}
+
+// ParsePlaylistID extracts a playlist ID from a Spotify playlist URI, URL or bare ID
+func ParsePlaylistID(input string) (spotify.ID, error) {
+	return ParseID("playlist", input)
+}
+
+// IsLink reports whether the input is a Spotify URI or URL rather than a name
+// or bare ID
+func IsLink(input string) bool {
+	return spotifyid.IsLink(input)
+}
@@ -0,0 +1,33 @@
+package spotify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zmb3/spotify/v2"
+)
+
+func TestParseID(t *testing.T) {
+	id, err := ParseID("track", "spotify:track:4uLU6hMCjMI75M1A2tKUQC")
+	assert.NoError(t, err)
+	assert.Equal(t, spotify.ID("4uLU6hMCjMI75M1A2tKUQC"), id)
+
+	_, err = ParseID("track", "Metallica - One")
+	assert.Error(t, err)
+}
+
+func TestParsePlaylistID(t *testing.T) {
+	id, err := ParsePlaylistID("https://open.spotify.com/playlist/37i9dQZF1DXcBWIGoYBM5M?si=abc")
+	assert.NoError(t, err)
+	assert.Equal(t, spotify.ID("37i9dQZF1DXcBWIGoYBM5M"), id)
+
+	_, err = ParsePlaylistID("spotify:track:4uLU6hMCjMI75M1A2tKUQC")
+	assert.Error(t, err)
+}
+
+func TestIsLink(t *testing.T) {
+	assert.True(t, IsLink("spotify:playlist:37i9dQZF1DXcBWIGoYBM5M"))
+	assert.True(t, IsLink(" https://open.spotify.com/playlist/37i9dQZF1DXcBWIGoYBM5M"))
+	assert.False(t, IsLink("37i9dQZF1DXcBWIGoYBM5M"))
+	assert.False(t, IsLink("My Mix"))
+}
@@ -0,0 +1,207 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// EditableBy reports whether the user can change the playlist's tracks
+func (p PlaylistInfo) EditableBy(userID string) bool {
+	return p.Owner == userID || p.Collaborative
+}
+
+// newPlaylistInfo converts a Spotify playlist into a PlaylistInfo
+func newPlaylistInfo(playlist spotify.SimplePlaylist, trackCount int) PlaylistInfo {
+	return PlaylistInfo{
+		ID:            string(playlist.ID),
+		Name:          playlist.Name,
+		Description:   playlist.Description,
+		TrackCount:    trackCount,
+		Owner:         playlist.Owner.ID,
+		Public:        playlist.IsPublic,
+		Collaborative: playlist.Collaborative,
+	}
+}
+
+// FindPlaylist finds a playlist by ID, URI, URL or name. Names are matched
+// against the current user's playlists; when several share the name, the one
+// the user owns wins.
+func (s *Service) FindPlaylist(ctx context.Context, ref string) (*PlaylistInfo, error) {
+	if s.client == nil {
+		return nil, fmt.Errorf("not authenticated with Spotify")
+	}
+
+	userID, err := s.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.findPlaylist(ctx, userID, ref, false)
+}
+
+// findEditablePlaylist finds a playlist the user can update by ID, URI, URL or
+// name. Playlists with the name that belong to someone else are ignored, so
+// a new playlist gets created instead.
+func (s *Service) findEditablePlaylist(ctx context.Context, userID, ref string) (*spotify.FullPlaylist, error) {
+	info, err := s.findPlaylist(ctx, userID, ref, true)
+	if err != nil || info == nil {
+		return nil, err
+	}
+
+	playlist, err := s.client.GetPlaylist(ctx, spotify.ID(info.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get full playlist details: %w", err)
+	}
+	return playlist, nil
+}
+
+// findPlaylist resolves ref to a playlist, returning nil if no playlist has
+// that name. With editable, only playlists the user can update qualify.
+func (s *Service) findPlaylist(ctx context.Context, userID, ref string, editable bool) (*PlaylistInfo, error) {
+	ref = strings.TrimSpace(ref)
+
+	var info *PlaylistInfo
+	if IsLink(ref) {
+		// URIs and URLs always point at a specific playlist
+		id, err := ParsePlaylistID(ref)
+		if err != nil {
+			return nil, err
+		}
+		info, err = s.playlistByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		playlists, err := s.GetUserPlaylists(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var matches []PlaylistInfo
+		for _, playlist := range playlists {
+			if playlist.Name == ref {
+				matches = append(matches, playlist)
+			}
+		}
+
+		if len(matches) > 0 {
+			match, err := pickPlaylist(matches, ref, userID, editable)
+			if err == nil && match == nil {
+				fmt.Fprintf(s.progress, "   ℹ️  Playlists named '%s' belong to other users, ignoring them\n", ref)
+			}
+			return match, err
+		}
+
+		// Names win over bare IDs, since a name could look like an ID
+		id, err := ParsePlaylistID(ref)
+		if err != nil {
+			return nil, nil
+		}
+		info, err = s.playlistByID(ctx, id)
+		if err != nil {
+			return nil, nil
+		}
+	}
+
+	if editable && !info.EditableBy(userID) {
+		return nil, &notEditableError{name: info.Name, owner: info.Owner}
+	}
+	return info, nil
+}
+
+// matchedByID reports whether the playlist was found by the bare ID in ref
+// rather than by its name, so ref isn't a name to give it
+func matchedByID(playlist *spotify.FullPlaylist, ref string) bool {
+	ref = strings.TrimSpace(ref)
+	return playlist.Name != ref && string(playlist.ID) == ref
+}
+
+// notEditableError is returned for playlists the user isn't allowed to change
+type notEditableError struct {
+	name  string
+	owner string
+}
+
+func (e *notEditableError) Error() string {
+	return fmt.Sprintf("playlist '%s' belongs to %s and can't be changed", e.name, e.owner)
+}
+
+// isPlaylistGone reports whether err means the playlist doesn't exist or
+// can't be changed by the user anymore, as opposed to a failed request
+func isPlaylistGone(err error) bool {
+	var notEditable *notEditableError
+	if errors.As(err, &notEditable) {
+		return true
+	}
+	var apiErr spotify.Error
+	return errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound
+}
+
+// playlistByID fetches a playlist's basic information
+func (s *Service) playlistByID(ctx context.Context, id spotify.ID) (*PlaylistInfo, error) {
+	playlist, err := s.client.GetPlaylist(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get playlist %s: %w", id, err)
+	}
+	info := newPlaylistInfo(playlist.SimplePlaylist, int(playlist.Tracks.Total))
+	return &info, nil
+}
+
+// pickPlaylist chooses among playlists sharing a name. With editable, only
+// playlists the user can update are considered; otherwise the user's own
+// playlist is preferred. It returns nil if none qualifies and an error when
+// the choice is ambiguous.
+func pickPlaylist(matches []PlaylistInfo, name, userID string, editable bool) (*PlaylistInfo, error) {
+	candidates := matches
+	if editable {
+		candidates = nil
+		for _, playlist := range matches {
+			if playlist.EditableBy(userID) {
+				candidates = append(candidates, playlist)
+			}
+		}
+	} else if len(matches) > 1 {
+		var owned []PlaylistInfo
+		for _, playlist := range matches {
+			if playlist.Owner == userID {
+				owned = append(owned, playlist)
+			}
+		}
+		if len(owned) > 0 {
+			candidates = owned
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &candidates[0], nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d playlists are named '%s', use the playlist URI instead:", len(candidates), name)
+	for _, playlist := range candidates {
+		fmt.Fprintf(&b, "\n  spotify:playlist:%s (owner: %s, %d tracks)", playlist.ID, playlist.Owner, playlist.TrackCount)
+	}
+	return nil, fmt.Errorf("%s", b.String())
+}
+
+// currentUserID returns the ID of the logged in user
+func (s *Service) currentUserID(ctx context.Context) (string, error) {
+	if s.userID != "" {
+		return s.userID, nil
+	}
+
+	user, err := s.client.CurrentUser(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get current user: %w", err)
+	}
+	s.userID = user.ID
+	return s.userID, nil
+}
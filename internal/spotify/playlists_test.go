@@ -0,0 +1,87 @@
+package spotify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmb3/spotify/v2"
+)
+
+func TestPlaylistInfo_EditableBy(t *testing.T) {
+	assert.True(t, PlaylistInfo{Owner: "me"}.EditableBy("me"))
+	assert.False(t, PlaylistInfo{Owner: "spotify"}.EditableBy("me"))
+	assert.True(t, PlaylistInfo{Owner: "friend", Collaborative: true}.EditableBy("me"))
+}
+
+func TestPickPlaylist(t *testing.T) {
+	mine := PlaylistInfo{ID: "mine", Name: "Chill", Owner: "me"}
+	mineToo := PlaylistInfo{ID: "mine-too", Name: "Chill", Owner: "me"}
+	followed := PlaylistInfo{ID: "followed", Name: "Chill", Owner: "spotify"}
+	shared := PlaylistInfo{ID: "shared", Name: "Chill", Owner: "friend", Collaborative: true}
+
+	tests := []struct {
+		name       string
+		matches    []PlaylistInfo
+		editable   bool
+		expectedID string
+		ambiguous  bool
+	}{
+		{name: "single match", matches: []PlaylistInfo{followed}, expectedID: "followed"},
+		{name: "own playlist preferred", matches: []PlaylistInfo{followed, mine}, expectedID: "mine"},
+		{name: "several own playlists", matches: []PlaylistInfo{mine, followed, mineToo}, ambiguous: true},
+		{name: "several followed playlists", matches: []PlaylistInfo{followed, shared}, ambiguous: true},
+		{name: "editable skips followed", matches: []PlaylistInfo{followed, mine}, editable: true, expectedID: "mine"},
+		{name: "editable collaborative", matches: []PlaylistInfo{followed, shared}, editable: true, expectedID: "shared"},
+		{name: "editable none", matches: []PlaylistInfo{followed}, editable: true},
+		{name: "editable several", matches: []PlaylistInfo{mine, shared}, editable: true, ambiguous: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := pickPlaylist(tt.matches, "Chill", "me", tt.editable)
+
+			if tt.ambiguous {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "playlists are named 'Chill'")
+				assert.Nil(t, match)
+				return
+			}
+
+			require.NoError(t, err)
+			if tt.expectedID == "" {
+				assert.Nil(t, match)
+				return
+			}
+			require.NotNil(t, match)
+			assert.Equal(t, tt.expectedID, match.ID)
+		})
+	}
+}
+
+func TestPickPlaylist_AmbiguityListsURIs(t *testing.T) {
+	matches := []PlaylistInfo{
+		{ID: "37i9dQZF1DXcBWIGoYBM5M", Name: "Chill", Owner: "me", TrackCount: 12},
+		{ID: "5ABHKGoOzxkaa28ttQV9sE", Name: "Chill", Owner: "me", TrackCount: 30},
+	}
+
+	_, err := pickPlaylist(matches, "Chill", "me", true)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "2 playlists are named 'Chill'")
+	assert.Contains(t, err.Error(), "spotify:playlist:37i9dQZF1DXcBWIGoYBM5M (owner: me, 12 tracks)")
+	assert.Contains(t, err.Error(), "spotify:playlist:5ABHKGoOzxkaa28ttQV9sE (owner: me, 30 tracks)")
+}
+
+func TestMatchedByID(t *testing.T) {
+	playlist := &spotify.FullPlaylist{}
+	playlist.ID = "37i9dQZF1DXcBWIGoYBM5M"
+	playlist.Name = "Road Trip"
+
+	assert.True(t, matchedByID(playlist, " 37i9dQZF1DXcBWIGoYBM5M"))
+	assert.False(t, matchedByID(playlist, "Road Trip"))
+
+	// A playlist whose name looks like an ID was found by name
+	playlist.Name = "37i9dQZF1DXcBWIGoYBM5M"
+	assert.False(t, matchedByID(playlist, "37i9dQZF1DXcBWIGoYBM5M"))
+}
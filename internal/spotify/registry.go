@@ -0,0 +1,63 @@
+package spotify
+
+import (
+	"context"
+	"fmt"
+
+	"auto-spotify/internal/registry"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// SetRegistry sets the registry of playlists created from files and prompts
+func (s *Service) SetRegistry(r *registry.Registry) {
+	s.registry = r
+}
+
+// Registry returns the playlist registry in use, if any
+func (s *Service) Registry() *registry.Registry {
+	return s.registry
+}
+
+// registeredPlaylist returns the playlist an earlier run created from the
+// source, forgetting it if it's gone, can no longer be changed or was
+// deleted by the user. Other errors are returned and the registry entry is
+// kept for the next run.
+func (s *Service) registeredPlaylist(ctx context.Context, userID, source string) (*spotify.FullPlaylist, error) {
+	entry, ok := s.registry.Lookup(source)
+	if !ok {
+		return nil, nil
+	}
+
+	playlist, err := s.findEditablePlaylist(ctx, userID, "spotify:playlist:"+entry.PlaylistID)
+	if err != nil && !isPlaylistGone(err) {
+		return nil, fmt.Errorf("failed to look up registered playlist '%s': %w", entry.Name, err)
+	}
+	if playlist != nil {
+		// Deleting a playlist in Spotify only unfollows it, so it still
+		// looks editable here
+		follows, err := s.client.UserFollowsPlaylist(ctx, playlist.ID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check if you follow registered playlist '%s': %w", entry.Name, err)
+		}
+		if len(follows) == 0 || !follows[0] {
+			s.logger.Warn("registered playlist was deleted, forgetting it",
+				"playlist", entry.PlaylistID, "name", entry.Name, "source", source)
+			s.registry.Forget(source)
+			return nil, nil
+		}
+	}
+	if playlist == nil {
+		s.logger.Warn("registered playlist is no longer available, forgetting it",
+			"playlist", entry.PlaylistID, "name", entry.Name, "source", source, "error", err)
+		s.registry.Forget(source)
+		return nil, nil
+	}
+
+	if playlist.Name != entry.Name {
+		fmt.Fprintf(s.progress, "📒 Found playlist '%s' (previously '%s') from an earlier run\n", playlist.Name, entry.Name)
+	} else {
+		fmt.Fprintf(s.progress, "📒 Found playlist '%s' from an earlier run\n", playlist.Name)
+	}
+	return playlist, nil
+}
@@ -0,0 +1,81 @@
+package spotify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"auto-spotify/internal/registry"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zmb3/spotify/v2"
+)
+
+func TestRegisteredPlaylist(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		owner   string
+		follows bool
+		found   bool
+		wantErr bool
+		kept    bool
+	}{
+		{name: "found", status: http.StatusOK, owner: "me", follows: true, found: true, kept: true},
+		{name: "deleted by the user", status: http.StatusOK, owner: "me", follows: false, kept: false},
+		{name: "deleted", status: http.StatusNotFound, kept: false},
+		{name: "not editable", status: http.StatusOK, owner: "someone", kept: false},
+		{name: "rate limited", status: http.StatusTooManyRequests, wantErr: true, kept: true},
+		{name: "server error", status: http.StatusInternalServerError, wantErr: true, kept: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if strings.HasSuffix(r.URL.Path, "/followers/contains") {
+					assert.Equal(t, "me", r.URL.Query().Get("ids"))
+					fmt.Fprintf(w, "[%t]", tt.follows)
+					return
+				}
+				if tt.status != http.StatusOK {
+					w.WriteHeader(tt.status)
+					fmt.Fprintf(w, `{"error":{"status":%d,"message":"%s"}}`, tt.status, http.StatusText(tt.status))
+					return
+				}
+				fmt.Fprintf(w, `{"id":"37i9dQZF1DXcBWIGoYBM5M","name":"Road Trip","owner":{"id":%q},"tracks":{"total":0}}`, tt.owner)
+			}))
+			defer server.Close()
+
+			reg, err := registry.Open(filepath.Join(t.TempDir(), "registry.json"))
+			require.NoError(t, err)
+			reg.Record("file:/songs.txt", "37i9dQZF1DXcBWIGoYBM5M", "Road Trip", "")
+
+			service := NewService("id", "secret", "http://localhost:8080/callback")
+			service.client = spotify.New(server.Client(), spotify.WithBaseURL(server.URL+"/"))
+			service.SetRegistry(reg)
+			var progress bytes.Buffer
+			service.SetProgress(&progress)
+
+			playlist, err := service.registeredPlaylist(context.Background(), "me", "file:/songs.txt")
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.found, playlist != nil)
+			if tt.found {
+				assert.Contains(t, progress.String(), "Found playlist 'Road Trip' from an earlier run")
+			}
+			_, kept := reg.Lookup("file:/songs.txt")
+			assert.Equal(t, tt.kept, kept)
+		})
+	}
+}
@@ -1,15 +1,22 @@
 package spotify
 
 import (
+	"bytes"
 	"context"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
 	"time"
 
+	"auto-spotify/internal/cache"
+	"auto-spotify/internal/history"
 	"auto-spotify/internal/openai"
+	"auto-spotify/internal/overrides"
+	"auto-spotify/internal/registry"
 
 	"github.com/zmb3/spotify/v2"
 	spotifyauth "github.com/zmb3/spotify/v2/auth"
@@ -20,35 +27,88 @@ import (
 type Service struct {
 	auth        *spotifyauth.Authenticator
 	client      *spotify.Client
+	httpClient  *http.Client
+	apiURL      string
 	clientID    string
 	redirectURL string
+	cache       *cache.Cache
+	overrides   *overrides.Overrides
+	registry    *registry.Registry
+	history     *history.History
+	logger      *slog.Logger
+	progress    io.Writer
+	market      string
+	clean       bool
+	visibility  Visibility
+	userID      string
 }
 
 // SearchResult represents a search result for a song
 type SearchResult struct {
-	Track  *spotify.FullTrack
-	Found  bool
-	Query  string
-	Reason string
+	Song        openai.Song
+	Track       *spotify.FullTrack
+	Found       bool
+	Skipped     bool
+	Unavailable bool // Matches exist but none are playable in the market
+	Explicit    bool // Only explicit versions exist and clean mode filtered them
+	Duplicate   bool // Resolved to a track already in the playlist
+	Trimmed     bool // Left out to keep the playlist within its target duration or track limit
+	Excluded    bool // Resolved to an excluded artist or track
+	Query       string
+	Reason      string
 }
 
+// Added reports whether the result's track goes into the playlist
+func (r SearchResult) Added() bool {
+	return r.Found && !r.Duplicate && !r.Trimmed && !r.Excluded
+}
+
+// PlaylistOptions controls how CreateOrUpdatePlaylist builds the playlist
+type PlaylistOptions struct {
+	ForceCreate       bool          // Always create a new playlist instead of updating one with the same name
+	Target            string        // Playlist to update as an ID, URI, URL or name (default: the playlist name)
+	Source            string        // Registry key of the file or prompts the playlist is generated from (optional)
+	Rename            bool          // Rename a playlist found through the registry to the new name
+	TargetDuration    time.Duration // Trim or top up the playlist to this total runtime (0 disables)
+	DurationTolerance time.Duration // How far the final runtime may be from the target
+	TopUp             TopUpFunc     // Supplies more songs when the playlist is too short (optional)
+	SkipTracks        []TrackInfo   // Tracks that must not be added, e.g. ones from a seed playlist
+	Exclusions        Exclusions    // Artists and tracks that are never added
+	Append            bool          // Add to an existing playlist instead of replacing its tracks
+	InsertPosition    int           // 1-based position to insert appended tracks at (0 for the end)
+	MaxTracks         int           // Cap on the total number of tracks in the playlist (0 for no cap)
+	Cover             []byte        // JPEG cover image to upload (optional)
+}
+
+// TopUpFunc returns additional songs when a duration-targeted playlist comes up
+// short. It receives every song tried so far and the missing runtime.
+type TopUpFunc func(ctx context.Context, have []openai.Song, missing time.Duration) ([]openai.Song, error)
+
 // PlaylistInfo represents basic playlist information
 type PlaylistInfo struct {
-	ID          string
-	Name        string
-	Description string
-	TrackCount  int
-	Owner       string
-	Public      bool
+	ID            string
+	Name          string
+	Description   string
+	TrackCount    int
+	Owner         string // Owner's user ID
+	Public        bool
+	Collaborative bool
 }
 
 // TrackInfo represents basic track information
 type TrackInfo struct {
-	ID     string
-	Title  string
-	Artist string
-	Album  string
-	Year   int
+	ID          string
+	Title       string
+	Artist      string
+	Artists     []string // All artists, starting with Artist
+	Album       string
+	Year        int
+	Duration    time.Duration
+	ISRC        string
+	Popularity  int       // 0-100
+	AddedAt     time.Time // When the track was added to the playlist (zero when unknown)
+	Position    int       // 0-based position in the playlist
+	Unavailable bool      // Not playable in the market the tracks were fetched for
 }
 
 // NewService creates a new Spotify service
@@ -62,6 +122,7 @@ func NewService(clientID, clientSecret, redirectURL string) *Service {
 			spotifyauth.ScopePlaylistReadPrivate,
 			spotifyauth.ScopePlaylistModifyPublic,
 			spotifyauth.ScopePlaylistModifyPrivate,
+			spotifyauth.ScopeImageUpload,
 		),
 	)
 
@@ -69,7 +130,92 @@ func NewService(clientID, clientSecret, redirectURL string) *Service {
 		auth:        auth,
 		clientID:    clientID,
 		redirectURL: redirectURL,
+		apiURL:      defaultAPIURL,
+		logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+		progress:    os.Stdout,
+	}
+}
+
+// SetLogger sets the logger for diagnostic messages
+func (s *Service) SetLogger(logger *slog.Logger) {
+	s.logger = logger
+}
+
+// Logger returns the logger diagnostics are written to
+func (s *Service) Logger() *slog.Logger {
+	return s.logger
+}
+
+// SetProgress sets where progress messages are printed (stdout by default)
+func (s *Service) SetProgress(w io.Writer) {
+	s.progress = w
+}
+
+// Progress returns the writer progress messages are printed to
+func (s *Service) Progress() io.Writer {
+	return s.progress
+}
+
+// SetCache sets the search result cache consulted by SearchSong (nil disables caching)
+func (s *Service) SetCache(c *cache.Cache) {
+	s.cache = c
+}
+
+// SetOverrides sets the manual match overrides consulted before searching
+func (s *Service) SetOverrides(o *overrides.Overrides) {
+	s.overrides = o
+}
+
+// Overrides returns the manual match overrides in use, if any
+func (s *Service) Overrides() *overrides.Overrides {
+	return s.overrides
+}
+
+// SetMarket sets the ISO 3166-1 alpha-2 country code used for searching and
+// fetching tracks. When empty, the current user's country is used.
+func (s *Service) SetMarket(market string) {
+	s.market = strings.ToUpper(strings.TrimSpace(market))
+}
+
+// Market returns the market in use, resolving it from the current user's
+// country if none was set
+func (s *Service) Market(ctx context.Context) string {
+	if s.market != "" || s.client == nil {
+		return s.market
+	}
+
+	user, err := s.client.CurrentUser(ctx)
+	if err != nil {
+		s.logger.Warn("failed to determine your country, searching without a market", "error", err)
+		return ""
 	}
+	s.market = user.Country
+	return s.market
+}
+
+// marketOptions returns the request options restricting results to the market
+func (s *Service) marketOptions(ctx context.Context) []spotify.RequestOption {
+	if market := s.Market(ctx); market != "" {
+		return []spotify.RequestOption{spotify.Market(market)}
+	}
+	return nil
+}
+
+// SetClean enables clean mode, which rejects tracks with explicit lyrics
+func (s *Service) SetClean(clean bool) {
+	s.clean = clean
+}
+
+// Clean reports whether clean mode is enabled
+func (s *Service) Clean() bool {
+	return s.clean
+}
+
+// isPlayable reports whether a track can be played in the requested market.
+// Spotify only reports playability when a market is passed, so tracks
+// without that information are assumed playable.
+func isPlayable(track *spotify.FullTrack) bool {
+	return track.IsPlayable == nil || *track.IsPlayable
 }
 
 // Authenticate handles the OAuth flow for Spotify
@@ -122,7 +268,7 @@ func (s *Service) Authenticate(ctx context.Context) error {
 
 	// Start HTTP server
 	go func() {
-		log.Printf("Starting HTTP server on %s for Spotify OAuth callback...", server.Addr)
+		s.logger.Debug("starting HTTP server for Spotify OAuth callback", "addr", server.Addr)
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			errCh <- fmt.Errorf("HTTP server error: %w", err)
 		}
@@ -132,7 +278,7 @@ func (s *Service) Authenticate(ctx context.Context) error {
 	time.Sleep(200 * time.Millisecond)
 
 	authURL := s.auth.AuthURL(state)
-	fmt.Printf("Please log in to Spotify by visiting the following page in your browser:\n%s\n\n", authURL)
+	fmt.Fprintf(s.progress, "Please log in to Spotify by visiting the following page in your browser:\n%s\n\n", authURL)
 
 	// Wait for either success or error
 	select {
@@ -143,8 +289,8 @@ func (s *Service) Authenticate(ctx context.Context) error {
 		server.Shutdown(shutdownCtx)
 
 		// Create client with the token
-		httpClient := s.auth.Client(ctx, token)
-		s.client = spotify.New(httpClient)
+		s.httpClient = s.auth.Client(ctx, token)
+		s.client = spotify.New(s.httpClient)
 
 		return nil
 	case err := <-errCh:
@@ -166,25 +312,84 @@ func (s *Service) Authenticate(ctx context.Context) error {
 
 // SearchSong searches for a song on Spotify
 func (s *Service) SearchSong(ctx context.Context, song openai.Song) *SearchResult {
-	// Try different search queries in order of preference
-	queries := []string{
-		fmt.Sprintf("artist:%s track:%s", song.Artist, song.Title),
-		fmt.Sprintf("%s %s", song.Artist, song.Title),
-		fmt.Sprintf("track:%s", song.Title),
+	// A track given with the song is used as is
+	if song.URI != "" {
+		if result := s.trackForURI(ctx, song); result != nil {
+			return result
+		}
+		// Without a title there is nothing to search for instead
+		if song.Title == "" {
+			return &SearchResult{Song: song, Query: "uri", Reason: song.Reason}
+		}
+	}
+
+	// Manual overrides always win over searching
+	if override, ok := s.overrides.Lookup(song.Artist, song.Title); ok {
+		if result := s.applyOverride(ctx, song, override); result != nil {
+			return result
+		}
+	}
+
+	market := s.Market(ctx)
+	opts := s.marketOptions(ctx)
+	if s.clean {
+		// Look further down the results so clean versions have a chance to show up
+		opts = append(opts, spotify.Limit(50))
+	}
+
+	// Reuse a previously resolved track when we have one
+	if track, ok := s.cache.Get(market, song.Artist, song.Title); ok && !(s.clean && track.Explicit) {
+		s.logger.Debug("search cache hit", "artist", song.Artist, "title", song.Title, "track", track.ID)
+		return &SearchResult{
+			Song:   song,
+			Track:  track,
+			Found:  true,
+			Query:  "cache",
+			Reason: song.Reason,
+		}
 	}
 
+	// Try different search queries in order of preference
+	queries := searchQueries(song)
+
+	unavailable := false
+	explicit := false
 	for _, query := range queries {
-		results, err := s.client.Search(ctx, query, spotify.SearchTypeTrack)
+		s.logger.Debug("searching Spotify", "query", query, "market", market)
+		results, err := s.client.Search(ctx, query, spotify.SearchTypeTrack, opts...)
 		if err != nil {
-			log.Printf("Search error for '%s': %v", query, err)
+			s.logger.Warn("search failed", "query", query, "error", err)
+			continue
+		}
+
+		if results.Tracks == nil {
 			continue
 		}
 
-		if results.Tracks != nil && len(results.Tracks.Tracks) > 0 {
+		// Ignore tracks that are greyed out in the market, and explicit
+		// tracks in clean mode so a clean version of the song can win
+		var playable []spotify.FullTrack
+		for _, track := range results.Tracks.Tracks {
+			if !isPlayable(&track) {
+				unavailable = true
+				continue
+			}
+			if s.clean && track.Explicit {
+				if s.isGoodMatch(song, &track) {
+					explicit = true
+				}
+				continue
+			}
+			playable = append(playable, track)
+		}
+
+		if len(playable) > 0 {
 			// Find the best match
-			for _, track := range results.Tracks.Tracks {
+			for _, track := range playable {
 				if s.isGoodMatch(song, &track) {
+					s.cache.Put(market, song.Artist, song.Title, &track)
 					return &SearchResult{
+						Song:   song,
 						Track:  &track,
 						Found:  true,
 						Query:  query,
@@ -193,9 +398,16 @@ func (s *Service) SearchSong(ctx context.Context, song openai.Song) *SearchResul
 				}
 			}
 
-			// If no perfect match, return the first result
+			// The song itself is explicit, don't fall back to an unrelated track
+			if explicit {
+				continue
+			}
+
+			// If no perfect match, return the first result. It isn't cached
+			// so a wrong guess doesn't stick until the cache expires.
 			return &SearchResult{
-				Track:  &results.Tracks.Tracks[0],
+				Song:   song,
+				Track:  &playable[0],
 				Found:  true,
 				Query:  query,
 				Reason: song.Reason,
@@ -204,12 +416,122 @@ func (s *Service) SearchSong(ctx context.Context, song openai.Song) *SearchResul
 	}
 
 	return &SearchResult{
-		Found:  false,
-		Query:  fmt.Sprintf("%s %s", song.Artist, song.Title),
+		Song:        song,
+		Found:       false,
+		Unavailable: unavailable,
+		Explicit:    explicit,
+		Query:       fmt.Sprintf("%s %s", song.Artist, song.Title),
+		Reason:      song.Reason,
+	}
+}
+
+// applyOverride resolves a manual override, returning nil if it can't be used
+func (s *Service) applyOverride(ctx context.Context, song openai.Song, override overrides.Override) *SearchResult {
+	s.logger.Debug("applying match override", "artist", song.Artist, "title", song.Title, "target", override.Target)
+	if override.Skip() {
+		return &SearchResult{
+			Song:    song,
+			Found:   false,
+			Skipped: true,
+			Query:   "override",
+			Reason:  song.Reason,
+		}
+	}
+
+	trackID, err := ParseTrackID(override.Target)
+	if err != nil {
+		s.logger.Warn("ignoring invalid override", "artist", song.Artist, "title", song.Title, "error", err)
+		return nil
+	}
+
+	track, err := s.client.GetTrack(ctx, trackID, s.marketOptions(ctx)...)
+	if err != nil {
+		s.logger.Warn("failed to fetch override track", "artist", song.Artist, "title", song.Title, "error", err)
+		return nil
+	}
+
+	if s.clean && track.Explicit {
+		return &SearchResult{
+			Song:     song,
+			Found:    false,
+			Explicit: true,
+			Query:    "override",
+			Reason:   song.Reason,
+		}
+	}
+
+	return &SearchResult{
+		Song:   song,
+		Track:  track,
+		Found:  true,
+		Query:  "override",
 		Reason: song.Reason,
 	}
 }
 
+// searchQueries returns the search queries for a song, most specific first
+func searchQueries(song openai.Song) []string {
+	if song.Artist == "" {
+		return []string{
+			fmt.Sprintf("track:%s", song.Title),
+			song.Title,
+		}
+	}
+
+	var queries []string
+	if song.Album != "" {
+		query := fmt.Sprintf("artist:%s track:%s album:%s", song.Artist, song.Title, song.Album)
+		if song.Year != 0 {
+			query += fmt.Sprintf(" year:%d", song.Year)
+		}
+		queries = append(queries, query)
+	}
+
+	return append(queries,
+		fmt.Sprintf("artist:%s track:%s", song.Artist, song.Title),
+		fmt.Sprintf("%s %s", song.Artist, song.Title),
+		fmt.Sprintf("track:%s", song.Title),
+	)
+}
+
+// trackForURI fetches the track given with the song. It returns nil if the
+// track can't be fetched.
+func (s *Service) trackForURI(ctx context.Context, song openai.Song) *SearchResult {
+	trackID, err := ParseTrackID(song.URI)
+	if err != nil {
+		s.logger.Warn("ignoring invalid track URI", "song", song.String(), "error", err)
+		return nil
+	}
+
+	s.logger.Debug("fetching track by URI", "uri", song.URI)
+	track, err := s.client.GetTrack(ctx, trackID, s.marketOptions(ctx)...)
+	if err != nil {
+		s.logger.Warn("failed to fetch track", "uri", song.URI, "error", err)
+		return nil
+	}
+
+	// Songs given only as a URI are named after their track
+	if song.Title == "" {
+		song.Artist = primaryArtist(track)
+		song.Title = track.Name
+	}
+
+	result := &SearchResult{
+		Song:   song,
+		Track:  track,
+		Found:  true,
+		Query:  "uri",
+		Reason: song.Reason,
+	}
+	switch {
+	case !isPlayable(track):
+		result.Found, result.Track, result.Unavailable = false, nil, true
+	case s.clean && track.Explicit:
+		result.Found, result.Track, result.Explicit = false, nil, true
+	}
+	return result
+}
+
 // isGoodMatch checks if a Spotify track is a good match for the requested song
 func (s *Service) isGoodMatch(requested openai.Song, track *spotify.FullTrack) bool {
 	// Normalize strings for comparison
@@ -222,8 +544,8 @@ func (s *Service) isGoodMatch(requested openai.Song, track *spotify.FullTrack) b
 
 	trackTitle := normalize(track.Name)
 
-	// Check if any artist matches
-	artistMatch := false
+	// Check if any artist matches, any artist will do when none was asked for
+	artistMatch := requestedArtist == ""
 	for _, artist := range track.Artists {
 		if normalize(artist.Name) == requestedArtist {
 			artistMatch = true
@@ -240,7 +562,7 @@ func (s *Service) isGoodMatch(requested openai.Song, track *spotify.FullTrack) b
 }
 
 // CreateOrUpdatePlaylist creates a new playlist or updates an existing one with the same name
-func (s *Service) CreateOrUpdatePlaylist(ctx context.Context, playlistResp *openai.PlaylistResponse, forceCreate bool) (*spotify.FullPlaylist, []SearchResult, error) {
+func (s *Service) CreateOrUpdatePlaylist(ctx context.Context, playlistResp *openai.PlaylistResponse, opts PlaylistOptions) (*spotify.FullPlaylist, []SearchResult, error) {
 	if s.client == nil {
 		return nil, nil, fmt.Errorf("not authenticated with Spotify")
 	}
@@ -251,41 +573,108 @@ func (s *Service) CreateOrUpdatePlaylist(ctx context.Context, playlistResp *open
 		return nil, nil, fmt.Errorf("failed to get current user: %w", err)
 	}
 
+	// Default the market to the user's country
+	if s.market == "" {
+		s.market = user.Country
+	}
+	s.userID = user.ID
+
 	var playlist *spotify.FullPlaylist
+	var existingTracks []TrackInfo
+	existingTotal := 0
+
+	// Try to find the existing playlist to update (unless forcing create)
+	if !opts.ForceCreate {
+		target := opts.Target
+		if target == "" {
+			target = playlistResp.PlaylistName
+		}
 
-	// Try to find existing playlist with the same name (unless forcing create)
-	if !forceCreate {
-		fmt.Printf("🔍 Searching for existing playlist '%s'...\n", playlistResp.PlaylistName)
-		existingPlaylist, err := s.findPlaylistByName(ctx, user.ID, playlistResp.PlaylistName)
-		if err != nil {
-			fmt.Printf("⚠️  Warning: Failed to search for existing playlists: %v\n", err)
-		} else if existingPlaylist != nil {
-			fmt.Printf("🔄 Found existing playlist '%s', updating...\n", playlistResp.PlaylistName)
+		// Playlists from earlier runs are found even after being renamed
+		var existingPlaylist *spotify.FullPlaylist
+		fromRegistry, byID := false, false
+		if opts.Target == "" && opts.Source != "" {
+			existingPlaylist, err = s.registeredPlaylist(ctx, user.ID, opts.Source)
+			if err != nil {
+				return nil, nil, err
+			}
+			fromRegistry = existingPlaylist != nil
+		}
+
+		if existingPlaylist == nil {
+			fmt.Fprintf(s.progress, "🔍 Searching for existing playlist '%s'...\n", target)
+			existingPlaylist, err = s.findEditablePlaylist(ctx, user.ID, target)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to find existing playlist: %w", err)
+			}
+			byID = opts.Target == "" && existingPlaylist != nil && matchedByID(existingPlaylist, target)
+		}
+
+		if existingPlaylist != nil {
 			playlist = existingPlaylist
 
-			// Clear existing tracks
-			fmt.Printf("   🧹 Clearing existing tracks...\n")
-			if err := s.clearPlaylist(ctx, playlist.ID); err != nil {
-				fmt.Printf("⚠️  Warning: Failed to clear existing playlist: %v\n", err)
-				// Continue anyway, we'll just add to the existing tracks
+			if opts.Append {
+				fmt.Fprintf(s.progress, "➕ Found existing playlist '%s', appending...\n", playlist.Name)
+				existingTracks, err = s.GetPlaylistTracks(ctx, string(playlist.ID))
+				if err != nil {
+					return nil, nil, fmt.Errorf("failed to read existing playlist: %w", err)
+				}
+				existingTotal = int(playlist.Tracks.Total)
+				s.saveVersion(playlist, existingTracks, "append")
+
+				// Keep the name and description, but apply a requested visibility
+				if err := s.syncPlaylistDetails(ctx, playlist, "", "", s.visibility); err != nil {
+					s.logger.Warn("failed to update playlist details", "playlist", playlist.ID, "error", err)
+				}
 			} else {
-				fmt.Printf("   ✅ Cleared existing tracks\n")
+				fmt.Fprintf(s.progress, "🔄 Found existing playlist '%s', updating...\n", playlist.Name)
+
+				// Keep the tracks about to be replaced so the update can be undone
+				if s.history != nil {
+					previous, err := s.GetPlaylistTracks(ctx, string(playlist.ID))
+					if err != nil {
+						return nil, nil, fmt.Errorf("failed to read existing playlist: %w", err)
+					}
+					s.saveVersion(playlist, previous, "update")
+				}
+
+				// Keep the name of a registered playlist the user may have
+				// renamed, and of a playlist named by its bare ID
+				name := playlistResp.PlaylistName
+				if (fromRegistry && !opts.Rename) || byID {
+					name = ""
+				}
+				if err := s.syncPlaylistDetails(ctx, playlist, name, playlistResp.Description, s.visibility); err != nil {
+					s.logger.Warn("failed to update playlist details", "playlist", playlist.ID, "error", err)
+				}
+
+				// Clear existing tracks
+				fmt.Fprintf(s.progress, "   🧹 Clearing existing tracks...\n")
+				if snapshotID, err := s.clearPlaylist(ctx, playlist.ID); err != nil {
+					s.logger.Warn("failed to clear existing playlist", "playlist", playlist.ID, "error", err)
+					// Continue anyway, we'll just add to the existing tracks
+				} else {
+					if snapshotID != "" {
+						playlist.SnapshotID = snapshotID
+					}
+					fmt.Fprintf(s.progress, "   ✅ Cleared existing tracks\n")
+				}
 			}
 		} else {
-			fmt.Printf("❌ No existing playlist found with name '%s'\n", playlistResp.PlaylistName)
+			fmt.Fprintf(s.progress, "❌ No existing playlist found with name '%s'\n", target)
 		}
 	}
 
 	// Create new playlist if we don't have one
 	if playlist == nil {
-		fmt.Printf("📝 Creating new playlist '%s'...\n", playlistResp.PlaylistName)
+		fmt.Fprintf(s.progress, "📝 Creating new playlist '%s'...\n", playlistResp.PlaylistName)
 		newPlaylist, err := s.client.CreatePlaylistForUser(
 			ctx,
 			user.ID,
 			playlistResp.PlaylistName,
 			playlistResp.Description,
-			false, // public
-			false, // collaborative
+			s.visibility == VisibilityPublic,
+			s.visibility == VisibilityCollaborative,
 		)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to create playlist: %w", err)
@@ -293,105 +682,180 @@ func (s *Service) CreateOrUpdatePlaylist(ctx context.Context, playlistResp *open
 		playlist = newPlaylist
 	}
 
-	// Search for songs and collect track IDs
-	var trackIDs []spotify.ID
-	var searchResults []SearchResult
-
-	fmt.Printf("🔍 Searching for %d songs...\n", len(playlistResp.Songs))
+	// Search for songs
+	fmt.Fprintf(s.progress, "🔍 Searching for %d songs...\n", len(playlistResp.Songs))
+	duplicates := newDuplicateTracker()
+	duplicates.add(opts.SkipTracks)
+	duplicates.add(existingTracks)
+	excluded := newExclusionMatcher(opts.Exclusions)
+	searchResults := s.resolveSongs(ctx, playlistResp.Songs, duplicates, excluded)
+
+	// Trim or top up to the target runtime
+	if opts.TargetDuration > 0 {
+		searchResults = s.fitToDuration(ctx, searchResults, duplicates, excluded, opts)
+	}
 
-	for i, song := range playlistResp.Songs {
-		fmt.Printf("  [%d/%d] Searching for: %s - %s\n", i+1, len(playlistResp.Songs), song.Artist, song.Title)
+	// Respect the cap on the total playlist length
+	if opts.MaxTracks > 0 {
+		if limited := applyTrackLimit(searchResults, opts.MaxTracks-len(existingTracks)); limited > 0 {
+			fmt.Fprintf(s.progress, "✂️  Left out %d songs to stay within %d tracks\n", limited, opts.MaxTracks)
+		}
+	}
 
-		result := s.SearchSong(ctx, song)
-		searchResults = append(searchResults, *result)
+	if err := s.cache.Save(); err != nil {
+		s.logger.Warn("failed to save search cache", "path", s.cache.Path(), "error", err)
+	}
 
-		if result.Found {
+	var trackIDs []spotify.ID
+	for _, result := range searchResults {
+		if result.Added() {
 			trackIDs = append(trackIDs, result.Track.ID)
-			fmt.Printf("    ✓ Found: %s - %s\n", result.Track.Artists[0].Name, result.Track.Name)
-		} else {
-			fmt.Printf("    ✗ Not found: %s - %s\n", song.Artist, song.Title)
 		}
 	}
 
-	// Add tracks to playlist (Spotify API has a limit of 100 tracks per request)
-	if len(trackIDs) > 0 {
-		const batchSize = 100
-		for i := 0; i < len(trackIDs); i += batchSize {
-			end := i + batchSize
-			if end > len(trackIDs) {
-				end = len(trackIDs)
-			}
+	// Add tracks to playlist
+	snapshotID, err := s.addTracks(ctx, playlist.ID, trackIDs, opts.InsertPosition, existingTotal)
+	if err != nil {
+		return playlist, searchResults, err
+	}
+	if snapshotID != "" {
+		playlist.SnapshotID = snapshotID
+	}
 
-			batch := trackIDs[i:end]
-			_, err := s.client.AddTracksToPlaylist(ctx, playlist.ID, batch...)
-			if err != nil {
-				return playlist, searchResults, fmt.Errorf("failed to add tracks to playlist: %w", err)
-			}
+	// Remember the playlist so later runs from the same source update it
+	if opts.Source != "" {
+		s.registry.Record(opts.Source, string(playlist.ID), playlist.Name, playlist.SnapshotID)
+		if err := s.registry.Save(); err != nil {
+			s.logger.Warn("failed to save playlist registry", "path", s.registry.Path(), "error", err)
+		}
+	}
+
+	if len(opts.Cover) > 0 {
+		fmt.Fprintf(s.progress, "🖼️  Uploading cover image...\n")
+		if err := s.client.SetPlaylistImage(ctx, playlist.ID, bytes.NewReader(opts.Cover)); err != nil {
+			s.logger.Warn("failed to upload cover image", "playlist", playlist.ID, "error", err)
 		}
 	}
 
 	return playlist, searchResults, nil
 }
 
-// CreatePlaylist creates a playlist on Spotify and adds the found tracks (legacy method)
-func (s *Service) CreatePlaylist(ctx context.Context, playlistResp *openai.PlaylistResponse) (*spotify.FullPlaylist, []SearchResult, error) {
-	return s.CreateOrUpdatePlaylist(ctx, playlistResp, true) // Force create new
-}
+// addTracks appends tracks to a playlist and, when the 1-based position is
+// within the existing tracks, moves them there. It returns the playlist's
+// new snapshot ID.
+func (s *Service) addTracks(ctx context.Context, playlistID spotify.ID, trackIDs []spotify.ID, position, existingTotal int) (string, error) {
+	if len(trackIDs) == 0 {
+		return "", nil
+	}
 
-// findPlaylistByName searches for a playlist by name in the user's playlists
-func (s *Service) findPlaylistByName(ctx context.Context, userID string, playlistName string) (*spotify.FullPlaylist, error) {
-	limit := 50
-	offset := 0
-	maxPlaylists := 200 // Safety limit to avoid infinite loops
-	totalChecked := 0
+	var snapshotID string
 
-	fmt.Printf("   🔍 Searching playlists for user %s...\n", userID)
+	// Spotify API has a limit of 100 tracks per request
+	const batchSize = 100
+	for i := 0; i < len(trackIDs); i += batchSize {
+		end := i + batchSize
+		if end > len(trackIDs) {
+			end = len(trackIDs)
+		}
 
-	for {
-		playlists, err := s.client.CurrentUsersPlaylists(ctx, spotify.Limit(limit), spotify.Offset(offset))
+		batch := trackIDs[i:end]
+		var err error
+		snapshotID, err = s.client.AddTracksToPlaylist(ctx, playlistID, batch...)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get playlists: %w", err)
+			return "", fmt.Errorf("failed to add tracks to playlist: %w", err)
+		}
+	}
+
+	if position > 0 && position <= existingTotal {
+		var err error
+		snapshotID, err = s.client.ReorderPlaylistTracks(ctx, playlistID, spotify.PlaylistReorderOptions{
+			RangeStart:   existingTotal,
+			RangeLength:  len(trackIDs),
+			InsertBefore: position - 1,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to move tracks to position %d: %w", position, err)
 		}
+	}
 
-		fmt.Printf("   📄 Checking %d playlists (offset %d)...\n", len(playlists.Playlists), offset)
+	return snapshotID, nil
+}
 
-		for _, playlist := range playlists.Playlists {
-			totalChecked++
-			fmt.Printf("   📋 Playlist %d: '%s' (owner: %s)\n", totalChecked, playlist.Name, playlist.Owner.ID)
-
-			// Match by name (playlist is in user's library, so ownership is less strict)
-			if playlist.Name == playlistName {
-				fmt.Printf("   ✅ Found matching playlist!\n")
-				// Get full playlist details
-				fullPlaylist, err := s.client.GetPlaylist(ctx, playlist.ID)
-				if err != nil {
-					return nil, fmt.Errorf("failed to get full playlist details: %w", err)
-				}
-				return fullPlaylist, nil
-			}
+// applyTrackLimit keeps at most room new tracks, marking the rest Trimmed.
+// It returns how many tracks were left out.
+func applyTrackLimit(results []SearchResult, room int) int {
+	if room < 0 {
+		room = 0
+	}
+
+	limited := 0
+	for i := range results {
+		if !results[i].Added() {
+			continue
+		}
+		if room > 0 {
+			room--
+			continue
 		}
+		results[i].Trimmed = true
+		limited++
+	}
 
-		// Check if we've seen all playlists or hit our safety limit
-		if len(playlists.Playlists) < limit || totalChecked >= maxPlaylists {
-			fmt.Printf("   📊 Finished searching. Checked %d total playlists.\n", totalChecked)
-			break
+	return limited
+}
+
+// CreatePlaylist creates a playlist on Spotify and adds the found tracks (legacy method)
+func (s *Service) CreatePlaylist(ctx context.Context, playlistResp *openai.PlaylistResponse) (*spotify.FullPlaylist, []SearchResult, error) {
+	return s.CreateOrUpdatePlaylist(ctx, playlistResp, PlaylistOptions{ForceCreate: true}) // Force create new
+}
+
+// resolveSongs searches for each song, flagging songs that resolve to an
+// excluded track and songs that resolve to a track (or the same song on
+// another album) that was already resolved
+func (s *Service) resolveSongs(ctx context.Context, songs []openai.Song, duplicates *duplicateTracker, excluded *exclusionMatcher) []SearchResult {
+	var searchResults []SearchResult
+
+	for i, song := range songs {
+		fmt.Fprintf(s.progress, "  [%d/%d] Searching for: %s\n", i+1, len(songs), song)
+
+		result := s.SearchSong(ctx, song)
+		if result.Found && excluded.excludes(result.Track) {
+			result.Excluded = true
+		} else if result.Found && duplicates.seen(string(result.Track.ID), primaryArtist(result.Track), result.Track.Name) {
+			result.Duplicate = true
+		}
+		searchResults = append(searchResults, *result)
+
+		if result.Excluded {
+			fmt.Fprintf(s.progress, "    ⛔ Excluded: %s - %s\n", result.Track.Artists[0].Name, result.Track.Name)
+		} else if result.Duplicate {
+			fmt.Fprintf(s.progress, "    ↺ Duplicate: %s - %s\n", result.Track.Artists[0].Name, result.Track.Name)
+		} else if result.Found {
+			fmt.Fprintf(s.progress, "    ✓ Found: %s - %s\n", result.Track.Artists[0].Name, result.Track.Name)
+		} else if result.Skipped {
+			fmt.Fprintf(s.progress, "    ⏭️  Skipped by override: %s\n", song)
+		} else if result.Explicit {
+			fmt.Fprintf(s.progress, "    🚫 Filtered explicit: %s\n", song)
+		} else if result.Unavailable {
+			fmt.Fprintf(s.progress, "    ✗ Not available in %s: %s\n", s.market, song)
+		} else {
+			fmt.Fprintf(s.progress, "    ✗ Not found: %s\n", song)
 		}
-		offset += limit
 	}
 
-	return nil, nil // Not found
+	return searchResults
 }
 
-// clearPlaylist removes all tracks from a playlist
-func (s *Service) clearPlaylist(ctx context.Context, playlistID spotify.ID) error {
+// clearPlaylist removes all tracks from a playlist and returns its new snapshot ID
+func (s *Service) clearPlaylist(ctx context.Context, playlistID spotify.ID) (string, error) {
 	// Get current tracks
 	tracks, err := s.client.GetPlaylistTracks(ctx, playlistID)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	if len(tracks.Tracks) == 0 {
-		return nil // Already empty
+		return "", nil // Already empty
 	}
 
 	// Build list of track IDs to remove
@@ -403,9 +867,11 @@ func (s *Service) clearPlaylist(ctx context.Context, playlistID spotify.ID) erro
 	}
 
 	if len(trackIDs) == 0 {
-		return nil
+		return "", nil
 	}
 
+	var snapshotID string
+
 	// Remove tracks in batches (Spotify API limit)
 	const batchSize = 100
 	for i := 0; i < len(trackIDs); i += batchSize {
@@ -415,13 +881,13 @@ func (s *Service) clearPlaylist(ctx context.Context, playlistID spotify.ID) erro
 		}
 
 		batch := trackIDs[i:end]
-		_, err := s.client.RemoveTracksFromPlaylist(ctx, playlistID, batch...)
+		snapshotID, err = s.client.RemoveTracksFromPlaylist(ctx, playlistID, batch...)
 		if err != nil {
-			return err
+			return "", err
 		}
 	}
 
-	return nil
+	return snapshotID, nil
 }
 
 // GetUserPlaylists retrieves all playlists for the current user
@@ -433,7 +899,6 @@ func (s *Service) GetUserPlaylists(ctx context.Context) ([]PlaylistInfo, error)
 	var allPlaylists []PlaylistInfo
 	limit := 50
 	offset := 0
-	maxPlaylists := 1000 // Safety limit
 
 	for {
 		playlists, err := s.client.CurrentUsersPlaylists(ctx, spotify.Limit(limit), spotify.Offset(offset))
@@ -442,24 +907,11 @@ func (s *Service) GetUserPlaylists(ctx context.Context) ([]PlaylistInfo, error)
 		}
 
 		for _, playlist := range playlists.Playlists {
-			playlistInfo := PlaylistInfo{
-				ID:          string(playlist.ID),
-				Name:        playlist.Name,
-				Description: playlist.Description,
-				TrackCount:  int(playlist.Tracks.Total),
-				Owner:       playlist.Owner.ID,
-				Public:      playlist.IsPublic,
-			}
-			allPlaylists = append(allPlaylists, playlistInfo)
-
-			// Safety check
-			if len(allPlaylists) >= maxPlaylists {
-				break
-			}
+			allPlaylists = append(allPlaylists, newPlaylistInfo(playlist, int(playlist.Tracks.Total)))
 		}
 
 		// Check if we've seen all playlists
-		if len(playlists.Playlists) < limit || len(allPlaylists) >= maxPlaylists {
+		if len(playlists.Playlists) < limit || offset+limit >= int(playlists.Total) {
 			break
 		}
 		offset += limit
@@ -480,52 +932,24 @@ func (s *Service) GetPlaylistTracks(ctx context.Context, playlistID string) ([]T
 	maxTracks := 10000 // Safety limit
 
 	spotifyID := spotify.ID(playlistID)
+	opts := s.marketOptions(ctx)
 
 	for {
 		tracks, err := s.client.GetPlaylistTracks(ctx, spotifyID,
-			spotify.Limit(limit),
-			spotify.Offset(offset),
+			append([]spotify.RequestOption{spotify.Limit(limit), spotify.Offset(offset)}, opts...)...,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get playlist tracks: %w", err)
 		}
 
-		for _, item := range tracks.Tracks {
+		for i, item := range tracks.Tracks {
 			if item.Track.ID == "" {
 				continue // Skip empty tracks
 			}
 
-			track := item.Track
-
-			// Get primary artist
-			var artist string
-			if len(track.Artists) > 0 {
-				artist = track.Artists[0].Name
-			}
-
-			// Get album name
-			var album string
-			if track.Album.Name != "" {
-				album = track.Album.Name
-			}
-
-			// Get release year
-			var year int
-			if track.Album.ReleaseDate != "" {
-				// Parse year from release date (format: "YYYY-MM-DD" or "YYYY")
-				if len(track.Album.ReleaseDate) >= 4 {
-					if y, err := time.Parse("2006", track.Album.ReleaseDate[:4]); err == nil {
-						year = y.Year()
-					}
-				}
-			}
-
-			trackInfo := TrackInfo{
-				ID:     string(track.ID),
-				Title:  track.Name,
-				Artist: artist,
-				Album:  album,
-				Year:   year,
+			trackInfo := newTrackInfo(&item.Track, offset+i)
+			if addedAt, err := time.Parse(spotify.TimestampLayout, item.AddedAt); err == nil {
+				trackInfo.AddedAt = addedAt
 			}
 			allTracks = append(allTracks, trackInfo)
 
@@ -544,3 +968,42 @@ func (s *Service) GetPlaylistTracks(ctx context.Context, playlistID string) ([]T
 
 	return allTracks, nil
 }
+
+// newTrackInfo converts a Spotify track at the given position into a TrackInfo
+func newTrackInfo(track *spotify.FullTrack, position int) TrackInfo {
+	// Keep the ID stored in the playlist rather than a relinked one
+	trackID := track.ID
+	if track.LinkedFrom != nil && track.LinkedFrom.ID != "" {
+		trackID = track.LinkedFrom.ID
+	}
+
+	// Get release year
+	var year int
+	if track.Album.ReleaseDate != "" {
+		// Parse year from release date (format: "YYYY-MM-DD" or "YYYY")
+		if len(track.Album.ReleaseDate) >= 4 {
+			if y, err := time.Parse("2006", track.Album.ReleaseDate[:4]); err == nil {
+				year = y.Year()
+			}
+		}
+	}
+
+	var artists []string
+	for _, artist := range track.Artists {
+		artists = append(artists, artist.Name)
+	}
+
+	return TrackInfo{
+		ID:          string(trackID),
+		Title:       track.Name,
+		Artist:      primaryArtist(track),
+		Artists:     artists,
+		Album:       track.Album.Name,
+		Year:        year,
+		Duration:    track.TimeDuration(),
+		ISRC:        track.ExternalIDs["isrc"],
+		Popularity:  track.Popularity,
+		Position:    position,
+		Unavailable: !isPlayable(track),
+	}
+}
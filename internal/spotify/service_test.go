@@ -1,9 +1,17 @@
 package spotify
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"auto-spotify/internal/cache"
 	"auto-spotify/internal/openai"
 
 	"github.com/stretchr/testify/assert"
@@ -203,6 +211,72 @@ func TestService_DefaultState(t *testing.T) {
 	assert.Nil(t, service.client)
 }
 
+func TestService_SetMarket(t *testing.T) {
+	service := NewService("test", "test", "http://localhost:8080/callback")
+
+	// Without a client the market can't be resolved from the user profile
+	assert.Equal(t, "", service.Market(context.Background()))
+
+	service.SetMarket(" de ")
+	assert.Equal(t, "DE", service.Market(context.Background()))
+	assert.Len(t, service.marketOptions(context.Background()), 1)
+}
+
+func TestService_SetClean(t *testing.T) {
+	service := NewService("test", "test", "http://localhost:8080/callback")
+	assert.False(t, service.clean)
+
+	service.SetClean(true)
+	assert.True(t, service.clean)
+}
+
+func TestIsPlayable(t *testing.T) {
+	playable := true
+	notPlayable := false
+
+	assert.True(t, isPlayable(&spotify.FullTrack{}))
+	assert.True(t, isPlayable(&spotify.FullTrack{IsPlayable: &playable}))
+	assert.False(t, isPlayable(&spotify.FullTrack{IsPlayable: &notPlayable}))
+}
+
+func TestApplyTrackLimit(t *testing.T) {
+	notFound := SearchResult{Found: false}
+	duplicate := SearchResult{Found: true, Duplicate: true}
+	excluded := SearchResult{Found: true, Excluded: true}
+
+	results := []SearchResult{
+		{Found: true},
+		notFound,
+		duplicate,
+		{Found: true},
+		excluded,
+		{Found: true},
+	}
+
+	limited := applyTrackLimit(results, 2)
+
+	assert.Equal(t, 1, limited)
+	assert.True(t, results[0].Added())
+	assert.True(t, results[3].Added())
+	assert.False(t, results[5].Added())
+	assert.True(t, results[5].Trimmed)
+	assert.False(t, results[1].Trimmed)
+	assert.False(t, results[2].Trimmed)
+	assert.False(t, results[4].Trimmed)
+	assert.False(t, results[4].Added())
+}
+
+func TestApplyTrackLimit_NoRoom(t *testing.T) {
+	results := []SearchResult{{Found: true}, {Found: true}}
+
+	// The existing playlist is already over the cap
+	limited := applyTrackLimit(results, -3)
+
+	assert.Equal(t, 2, limited)
+	assert.False(t, results[0].Added())
+	assert.False(t, results[1].Added())
+}
+
 // Benchmark test for service creation
 func BenchmarkNewService(b *testing.B) {
 	clientID := "benchmark-client-id"
@@ -238,3 +312,89 @@ func BenchmarkSearchResult_Creation(b *testing.B) {
 		_ = result
 	}
 }
+
+func TestSearchQueries(t *testing.T) {
+	assert.Equal(t, []string{
+		"artist:Toto track:Africa",
+		"Toto Africa",
+		"track:Africa",
+	}, searchQueries(openai.Song{Artist: "Toto", Title: "Africa"}))
+
+	assert.Equal(t, []string{
+		"artist:Toto track:Africa album:Toto IV year:1982",
+		"artist:Toto track:Africa",
+		"Toto Africa",
+		"track:Africa",
+	}, searchQueries(openai.Song{Artist: "Toto", Title: "Africa", Album: "Toto IV", Year: 1982}))
+
+	assert.Equal(t, []string{
+		"track:Stand by Me",
+		"Stand by Me",
+	}, searchQueries(openai.Song{Title: "Stand by Me"}))
+}
+
+func TestSearchSong_InvalidURIWithoutTitle(t *testing.T) {
+	service := NewService("test-id", "test-secret", "http://localhost:8080/callback")
+
+	result := service.SearchSong(context.Background(), openai.Song{URI: "spotify:track:nope"})
+
+	assert.False(t, result.Found)
+	assert.Equal(t, "uri", result.Query)
+}
+
+func TestNewTrackInfo(t *testing.T) {
+	track := &spotify.FullTrack{
+		SimpleTrack: spotify.SimpleTrack{
+			ID:       "track1",
+			Name:     "One More Time",
+			Duration: 320000,
+			Artists:  []spotify.SimpleArtist{{Name: "Daft Punk"}, {Name: "Romanthony"}},
+		},
+		Album:       spotify.SimpleAlbum{Name: "Discovery", ReleaseDate: "2001-03-07"},
+		ExternalIDs: map[string]string{"isrc": "GBDUW0000053"},
+		Popularity:  78,
+	}
+
+	info := newTrackInfo(track, 3)
+
+	assert.Equal(t, "track1", info.ID)
+	assert.Equal(t, "Daft Punk", info.Artist)
+	assert.Equal(t, []string{"Daft Punk", "Romanthony"}, info.Artists)
+	assert.Equal(t, 2001, info.Year)
+	assert.Equal(t, 320*time.Second, info.Duration)
+	assert.Equal(t, "GBDUW0000053", info.ISRC)
+	assert.Equal(t, 78, info.Popularity)
+	assert.Equal(t, 3, info.Position)
+}
+
+func TestSearchSong_CachesOnlyGoodMatches(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		name, artist := "Africa", "Toto"
+		if !strings.Contains(r.URL.Query().Get("q"), "Africa") {
+			name, artist = "Something Else", "Someone Else"
+		}
+		fmt.Fprintf(w, `{"tracks":{"items":[{"id":"1","name":%q,"artists":[{"name":%q}]}]}}`, name, artist)
+	}))
+	defer server.Close()
+
+	searchCache, err := cache.Open(filepath.Join(t.TempDir(), "cache.json"), time.Hour)
+	require.NoError(t, err)
+
+	service := NewService("id", "secret", "http://localhost:8080/callback")
+	service.client = spotify.New(server.Client(), spotify.WithBaseURL(server.URL+"/"))
+	service.SetMarket("US")
+	service.SetCache(searchCache)
+
+	// The first result is used as a fallback, but not cached
+	result := service.SearchSong(context.Background(), openai.Song{Artist: "Queen", Title: "Bohemian Rhapsody"})
+	assert.True(t, result.Found)
+	_, cached := searchCache.Get("US", "Queen", "Bohemian Rhapsody")
+	assert.False(t, cached)
+
+	result = service.SearchSong(context.Background(), openai.Song{Artist: "Toto", Title: "Africa"})
+	assert.True(t, result.Found)
+	track, cached := searchCache.Get("US", "Toto", "Africa")
+	require.True(t, cached)
+	assert.Equal(t, "Africa", track.Name)
+}
@@ -0,0 +1,63 @@
+// Package spotifyid parses the Spotify URIs, open.spotify.com URLs and bare
+// IDs that name tracks and playlists, for both the Spotify client and the
+// playlist file readers.
+package spotifyid
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// idPattern matches a base62 Spotify ID
+var idPattern = regexp.MustCompile(`^[0-9A-Za-z]{22}$`)
+
+// Parse extracts a Spotify ID of the given kind ("track", "playlist", ...) from a
+// URI (spotify:track:ID), an open.spotify.com URL or a bare ID
+func Parse(kind, input string) (string, error) {
+	input = strings.TrimSpace(input)
+
+	var id string
+	switch {
+	case strings.HasPrefix(input, "spotify:"):
+		parts := strings.Split(input, ":")
+		if len(parts) != 3 || parts[1] != kind {
+			return "", fmt.Errorf("not a Spotify %s URI: %s", kind, input)
+		}
+		id = parts[2]
+	case strings.HasPrefix(input, "http://") || strings.HasPrefix(input, "https://"):
+		u, err := url.Parse(input)
+		if err != nil || !strings.HasSuffix(u.Hostname(), "spotify.com") {
+			return "", fmt.Errorf("not a Spotify URL: %s", input)
+		}
+		// Paths look like /track/ID or /intl-de/track/ID
+		segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+		for i := 0; i < len(segments)-1; i++ {
+			if segments[i] == kind {
+				id = segments[i+1]
+				break
+			}
+		}
+		if id == "" {
+			return "", fmt.Errorf("not a Spotify %s URL: %s", kind, input)
+		}
+	default:
+		id = input
+	}
+
+	if !idPattern.MatchString(id) {
+		return "", fmt.Errorf("invalid Spotify %s ID: %s", kind, id)
+	}
+
+	return id, nil
+}
+
+// IsLink reports whether the input is a Spotify URI or URL rather than a name
+// or bare ID
+func IsLink(input string) bool {
+	input = strings.TrimSpace(input)
+	return strings.HasPrefix(input, "spotify:") ||
+		strings.HasPrefix(input, "http://") ||
+		strings.HasPrefix(input, "https://")
+}
@@ -0,0 +1,79 @@
+package spotifyid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		kind       string
+		input      string
+		expectedID string
+		expectErr  bool
+	}{
+		{
+			name:       "track URI",
+			kind:       "track",
+			input:      "spotify:track:4uLU6hMCjMI75M1A2tKUQC",
+			expectedID: "4uLU6hMCjMI75M1A2tKUQC",
+		},
+		{
+			name:       "track URL with query",
+			kind:       "track",
+			input:      "https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC?si=abc123",
+			expectedID: "4uLU6hMCjMI75M1A2tKUQC",
+		},
+		{
+			name:       "localized URL",
+			kind:       "track",
+			input:      "https://open.spotify.com/intl-de/track/4uLU6hMCjMI75M1A2tKUQC",
+			expectedID: "4uLU6hMCjMI75M1A2tKUQC",
+		},
+		{
+			name:       "bare ID",
+			kind:       "playlist",
+			input:      "  37i9dQZF1DXcBWIGoYBM5M ",
+			expectedID: "37i9dQZF1DXcBWIGoYBM5M",
+		},
+		{
+			name:      "wrong kind of URI",
+			kind:      "track",
+			input:     "spotify:playlist:37i9dQZF1DXcBWIGoYBM5M",
+			expectErr: true,
+		},
+		{
+			name:      "non-Spotify URL",
+			kind:      "track",
+			input:     "https://example.com/track/4uLU6hMCjMI75M1A2tKUQC",
+			expectErr: true,
+		},
+		{
+			name:      "not an ID",
+			kind:      "track",
+			input:     "Metallica - One",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := Parse(tt.kind, tt.input)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedID, id)
+		})
+	}
+}
+
+func TestIsLink(t *testing.T) {
+	assert.True(t, IsLink("spotify:playlist:37i9dQZF1DXcBWIGoYBM5M"))
+	assert.True(t, IsLink(" https://open.spotify.com/playlist/37i9dQZF1DXcBWIGoYBM5M"))
+	assert.False(t, IsLink("37i9dQZF1DXcBWIGoYBM5M"))
+	assert.False(t, IsLink("My Mix"))
+}
@@ -0,0 +1,49 @@
+// Package statefile reads and writes the JSON files auto-spotify keeps its
+// state in between runs: the search cache, the playlist registry and the
+// playlist history.
+package statefile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Load decodes the JSON file at path into v. A missing or empty file leaves
+// v as it is. kind names the file in errors, e.g. "cache".
+func Load(path, kind string, v any) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s file %s: %w", kind, path, err)
+	}
+
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, v); err != nil {
+			return fmt.Errorf("failed to parse %s file %s: %w", kind, path, err)
+		}
+	}
+	return nil
+}
+
+// Write replaces the file at path with data, creating its directory if
+// needed. The data goes to a temporary file first so a crash never leaves a
+// truncated file behind.
+func Write(path, kind string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s directory: %w", kind, err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s file: %w", kind, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace %s file: %w", kind, err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package statefile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Missing(t *testing.T) {
+	entries := map[string]int{"kept": 1}
+
+	require.NoError(t, Load(filepath.Join(t.TempDir(), "missing.json"), "cache", &entries))
+	assert.Equal(t, map[string]int{"kept": 1}, entries)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry.json")
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0644))
+
+	var entries map[string]int
+	err := Load(path, "registry", &entries)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse registry file")
+}
+
+func TestWriteAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "history.json")
+
+	require.NoError(t, Write(path, "history", []byte(`{"a":1}`)))
+	_, err := os.Stat(path + ".tmp")
+	assert.True(t, os.IsNotExist(err), "temporary file should be renamed")
+
+	var entries map[string]int
+	require.NoError(t, Load(path, "history", &entries))
+	assert.Equal(t, map[string]int{"a": 1}, entries)
+}
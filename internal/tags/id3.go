@@ -0,0 +1,354 @@
+package tags
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// id3Frames maps ID3v2.3/2.4 and ID3v2.2 frame IDs to the fields they set
+var id3Frames = map[string]string{
+	"TIT2": "title", "TT2": "title",
+	"TPE1": "artist", "TP1": "artist",
+	"TPE2": "albumartist", "TP2": "albumartist",
+	"TALB": "album", "TAL": "album",
+	"TYER": "year", "TYE": "year", "TDRC": "year", "TDOR": "year",
+	"TCON": "genre", "TCO": "genre",
+}
+
+// readID3 reads an ID3v2 tag, falling back to an ID3v1 tag at the end of the
+// file for fields the ID3v2 tag doesn't set
+func readID3(r io.ReadSeeker) (*Tags, error) {
+	var header [10]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, fmt.Errorf("invalid ID3 header: %w", err)
+	}
+
+	version := header[3]
+	if version < 2 || version > 4 {
+		return nil, fmt.Errorf("unsupported ID3v2.%d tag", version)
+	}
+	flags := header[5]
+	size := syncsafe(header[6:10])
+	if size > maxTagSize {
+		return nil, fmt.Errorf("ID3 tag of %d bytes is too large", size)
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, fmt.Errorf("truncated ID3 tag: %w", err)
+	}
+
+	// ID3v2.4 unsynchronises frame by frame, older versions the whole tag
+	if flags&0x80 != 0 && version < 4 {
+		data = resync(data)
+	}
+
+	if flags&0x40 != 0 && version > 2 && len(data) >= 4 {
+		// Skip the extended header
+		extSize := int(binary.BigEndian.Uint32(data[:4]))
+		if version == 4 {
+			extSize = syncsafe(data[:4])
+		} else {
+			extSize += 4
+		}
+		if extSize > len(data) {
+			return nil, fmt.Errorf("invalid ID3 extended header")
+		}
+		data = data[extSize:]
+	}
+
+	tags := &Tags{}
+	for len(data) > 0 {
+		id, body, rest, ok := nextID3Frame(data, version)
+		if !ok {
+			break
+		}
+		data = rest
+
+		field, known := id3Frames[id]
+		if !known || len(body) == 0 {
+			continue
+		}
+		values := decodeID3Text(body)
+		if len(values) == 0 {
+			continue
+		}
+
+		switch field {
+		case "title":
+			tags.Title = values[0]
+		case "artist":
+			tags.Artist = values[0]
+		case "albumartist":
+			tags.AlbumArtist = values[0]
+		case "album":
+			tags.Album = values[0]
+		case "year":
+			if tags.Year == 0 || id == "TDRC" {
+				tags.Year = parseYear(values[0])
+			}
+		case "genre":
+			for _, value := range values {
+				for _, genre := range id3Genres(value) {
+					tags.addGenre(genre)
+				}
+			}
+		}
+	}
+
+	if fallback, err := readID3v1(r); err == nil && fallback != nil {
+		tags.fill(fallback)
+	}
+
+	return tags, nil
+}
+
+// nextID3Frame splits the first frame off data. It reports false at the
+// padding after the last frame or when the frame is broken.
+func nextID3Frame(data []byte, version byte) (id string, body, rest []byte, ok bool) {
+	headerSize := 10
+	if version == 2 {
+		headerSize = 6
+	}
+	if len(data) < headerSize || data[0] == 0 {
+		return "", nil, nil, false
+	}
+
+	var size int
+	var frameFlags uint16
+	switch version {
+	case 2:
+		id = string(data[:3])
+		size = int(data[3])<<16 | int(data[4])<<8 | int(data[5])
+	case 3:
+		id = string(data[:4])
+		size = int(binary.BigEndian.Uint32(data[4:8]))
+		frameFlags = binary.BigEndian.Uint16(data[8:10])
+	default:
+		id = string(data[:4])
+		size = syncsafe(data[4:8])
+		frameFlags = binary.BigEndian.Uint16(data[8:10])
+	}
+	if size < 0 || headerSize+size > len(data) {
+		return "", nil, nil, false
+	}
+
+	body = data[headerSize : headerSize+size]
+	rest = data[headerSize+size:]
+
+	if version == 4 {
+		// Data length indicator and unsynchronisation flags
+		if frameFlags&0x0001 != 0 && len(body) >= 4 {
+			body = body[4:]
+		}
+		if frameFlags&0x0002 != 0 {
+			body = resync(body)
+		}
+	}
+	// Compressed and encrypted frames are skipped
+	if (version == 3 && frameFlags&0x00c0 != 0) || (version == 4 && frameFlags&0x000c != 0) {
+		body = nil
+	}
+
+	return id, body, rest, true
+}
+
+// syncsafe decodes a 28-bit integer stored in the low 7 bits of 4 bytes
+func syncsafe(b []byte) int {
+	return int(b[0]&0x7f)<<21 | int(b[1]&0x7f)<<14 | int(b[2]&0x7f)<<7 | int(b[3]&0x7f)
+}
+
+// resync undoes ID3 unsynchronisation, which inserts a zero byte after 0xFF
+func resync(data []byte) []byte {
+	return bytes.ReplaceAll(data, []byte{0xff, 0x00}, []byte{0xff})
+}
+
+// decodeID3Text decodes a text frame into its values. ID3v2.4 separates
+// multiple values with a zero character.
+func decodeID3Text(body []byte) []string {
+	encoding, text := body[0], body[1:]
+
+	var decoded string
+	switch encoding {
+	case 0:
+		decoded = latin1(text)
+	case 1:
+		decoded = decodeUTF16(text, nil)
+	case 2:
+		decoded = decodeUTF16(text, binary.BigEndian)
+	case 3:
+		decoded = string(text)
+	default:
+		return nil
+	}
+
+	var values []string
+	for _, value := range strings.Split(decoded, "\x00") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// latin1 decodes ISO-8859-1 text
+func latin1(b []byte) string {
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
+
+// decodeUTF16 decodes UTF-16 text. Without a byte order, every value starts
+// with a byte order mark.
+func decodeUTF16(b []byte, order binary.ByteOrder) string {
+	withBOM := order == nil
+	if withBOM {
+		order = binary.LittleEndian
+	}
+
+	var units []uint16
+	valueStart := true
+	for i := 0; i+1 < len(b); i += 2 {
+		if withBOM && valueStart {
+			valueStart = false
+			switch {
+			case b[i] == 0xff && b[i+1] == 0xfe:
+				order = binary.LittleEndian
+				continue
+			case b[i] == 0xfe && b[i+1] == 0xff:
+				order = binary.BigEndian
+				continue
+			}
+		}
+		unit := order.Uint16(b[i:])
+		units = append(units, unit)
+		valueStart = unit == 0
+	}
+	return string(utf16.Decode(units))
+}
+
+// readID3v1 reads the 128 byte ID3v1 tag at the end of a file. It returns nil
+// without an error when there is none.
+func readID3v1(r io.ReadSeeker) (*Tags, error) {
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	if end < 128 {
+		return nil, nil
+	}
+	if _, err := r.Seek(end-128, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	var tag [128]byte
+	if _, err := io.ReadFull(r, tag[:]); err != nil {
+		return nil, err
+	}
+	if string(tag[:3]) != "TAG" {
+		return nil, nil
+	}
+
+	field := func(b []byte) string {
+		return strings.TrimSpace(latin1(bytes.TrimRight(b, "\x00 ")))
+	}
+	tags := &Tags{
+		Title:  field(tag[3:33]),
+		Artist: field(tag[33:63]),
+		Album:  field(tag[63:93]),
+		Year:   parseYear(field(tag[93:97])),
+	}
+	if int(tag[127]) < len(id3v1Genres) {
+		tags.addGenre(id3v1Genres[tag[127]])
+	}
+	return tags, nil
+}
+
+// fill sets the fields t is missing from other
+func (t *Tags) fill(other *Tags) {
+	if t.Title == "" {
+		t.Title = other.Title
+	}
+	if t.Artist == "" {
+		t.Artist = other.Artist
+	}
+	if t.Album == "" {
+		t.Album = other.Album
+	}
+	if t.Year == 0 {
+		t.Year = other.Year
+	}
+	if len(t.Genres) == 0 {
+		t.Genres = other.Genres
+	}
+}
+
+// genreRefPattern matches ID3v1 genre references in TCON frames like "(17)"
+var genreRefPattern = regexp.MustCompile(`^\((\d+|RX|CR)\)`)
+
+// id3Genres resolves a TCON value like "Rock", "17", "(17)" or "(17)Hard
+// Rock" into genre names
+func id3Genres(value string) []string {
+	var genres []string
+	for {
+		match := genreRefPattern.FindStringSubmatch(value)
+		if match == nil {
+			break
+		}
+		value = value[len(match[0]):]
+		genres = append(genres, id3GenreName(match[1]))
+	}
+
+	value = strings.TrimSpace(value)
+	if value != "" {
+		// A refinement replaces the reference it follows
+		if len(genres) > 0 {
+			genres = genres[:len(genres)-1]
+		}
+		genres = append(genres, id3GenreName(value))
+	}
+	return genres
+}
+
+// id3GenreName resolves a numeric ID3v1 genre reference, leaving names as they are
+func id3GenreName(value string) string {
+	switch value {
+	case "RX":
+		return "Remix"
+	case "CR":
+		return "Cover"
+	}
+	if index, err := strconv.Atoi(value); err == nil && index >= 0 && index < len(id3v1Genres) {
+		return id3v1Genres[index]
+	}
+	return value
+}
+
+// id3v1Genres are the genres ID3v1 tags refer to by index, including the
+// Winamp extensions
+var id3v1Genres = []string{
+	"Blues", "Classic Rock", "Country", "Dance", "Disco", "Funk", "Grunge", "Hip-Hop",
+	"Jazz", "Metal", "New Age", "Oldies", "Other", "Pop", "R&B", "Rap",
+	"Reggae", "Rock", "Techno", "Industrial", "Alternative", "Ska", "Death Metal", "Pranks",
+	"Soundtrack", "Euro-Techno", "Ambient", "Trip-Hop", "Vocal", "Jazz+Funk", "Fusion", "Trance",
+	"Classical", "Instrumental", "Acid", "House", "Game", "Sound Clip", "Gospel", "Noise",
+	"Alternative Rock", "Bass", "Soul", "Punk", "Space", "Meditative", "Instrumental Pop", "Instrumental Rock",
+	"Ethnic", "Gothic", "Darkwave", "Techno-Industrial", "Electronic", "Pop-Folk", "Eurodance", "Dream",
+	"Southern Rock", "Comedy", "Cult", "Gangsta", "Top 40", "Christian Rap", "Pop/Funk", "Jungle",
+	"Native American", "Cabaret", "New Wave", "Psychedelic", "Rave", "Showtunes", "Trailer", "Lo-Fi",
+	"Tribal", "Acid Punk", "Acid Jazz", "Polka", "Retro", "Musical", "Rock & Roll", "Hard Rock",
+	"Folk", "Folk-Rock", "National Folk", "Swing", "Fast Fusion", "Bebop", "Latin", "Revival",
+	"Celtic", "Bluegrass", "Avantgarde", "Gothic Rock", "Progressive Rock", "Psychedelic Rock", "Symphonic Rock", "Slow Rock",
+	"Big Band", "Chorus", "Easy Listening", "Acoustic", "Humour", "Speech", "Chanson", "Opera",
+	"Chamber Music", "Sonata", "Symphony", "Booty Bass", "Primus", "Porn Groove", "Satire", "Slow Jam",
+	"Club", "Tango", "Samba", "Folklore", "Ballad", "Power Ballad", "Rhythmic Soul", "Freestyle",
+	"Duet", "Punk Rock", "Drum Solo", "A Cappella", "Euro-House", "Dance Hall",
+}
@@ -0,0 +1,155 @@
+package tags
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// id3Frame builds an ID3v2.3 or 2.4 frame
+func id3Frame(version byte, id string, body []byte) []byte {
+	var frame bytes.Buffer
+	frame.WriteString(id)
+	size := make([]byte, 4)
+	if version == 4 {
+		copy(size, syncsafeBytes(len(body)))
+	} else {
+		binary.BigEndian.PutUint32(size, uint32(len(body)))
+	}
+	frame.Write(size)
+	frame.Write([]byte{0, 0})
+	frame.Write(body)
+	return frame.Bytes()
+}
+
+// id3Text builds the body of a text frame
+func id3Text(encoding byte, text string) []byte {
+	switch encoding {
+	case 1:
+		body := []byte{1, 0xff, 0xfe}
+		for _, unit := range utf16.Encode([]rune(text)) {
+			body = binary.LittleEndian.AppendUint16(body, unit)
+		}
+		return body
+	default:
+		return append([]byte{encoding}, text...)
+	}
+}
+
+// id3Tag builds an ID3v2 tag with the frames and some padding
+func id3Tag(version byte, frames ...[]byte) []byte {
+	body := bytes.Join(frames, nil)
+	body = append(body, make([]byte, 16)...)
+
+	var tag bytes.Buffer
+	tag.WriteString("ID3")
+	tag.Write([]byte{version, 0, 0})
+	tag.Write(syncsafeBytes(len(body)))
+	tag.Write(body)
+	return tag.Bytes()
+}
+
+func syncsafeBytes(n int) []byte {
+	return []byte{byte(n >> 21 & 0x7f), byte(n >> 14 & 0x7f), byte(n >> 7 & 0x7f), byte(n & 0x7f)}
+}
+
+// id3v1Tag builds the 128 byte ID3v1 tag
+func id3v1Tag(title, artist, album, year string, genre byte) []byte {
+	tag := make([]byte, 128)
+	copy(tag, "TAG")
+	copy(tag[3:33], title)
+	copy(tag[33:63], artist)
+	copy(tag[63:93], album)
+	copy(tag[93:97], year)
+	tag[127] = genre
+	return tag
+}
+
+func TestRead_ID3v23(t *testing.T) {
+	file := id3Tag(3,
+		id3Frame(3, "TIT2", id3Text(1, "Für Elise")),
+		id3Frame(3, "TPE1", id3Text(0, "Beethoven")),
+		id3Frame(3, "TALB", id3Text(0, "Classics")),
+		id3Frame(3, "TYER", id3Text(0, "1810")),
+		id3Frame(3, "TCON", id3Text(0, "(32)")),
+		id3Frame(3, "APIC", []byte{0, 1, 2, 3}),
+	)
+	file = append(file, make([]byte, 256)...) // Audio
+
+	tags, err := Read(bytes.NewReader(file))
+
+	require.NoError(t, err)
+	assert.Equal(t, &Tags{Title: "Für Elise", Artist: "Beethoven", Album: "Classics", Year: 1810, Genres: []string{"Classical"}}, tags)
+}
+
+func TestRead_ID3v24(t *testing.T) {
+	file := id3Tag(4,
+		id3Frame(4, "TIT2", id3Text(3, "Africa")),
+		id3Frame(4, "TPE1", id3Text(3, "Toto")),
+		id3Frame(4, "TPE2", id3Text(3, "Toto")),
+		id3Frame(4, "TDRC", id3Text(3, "1982-04-08")),
+		id3Frame(4, "TCON", id3Text(3, "Rock\x00Soft Rock")),
+	)
+
+	tags, err := Read(bytes.NewReader(file))
+
+	require.NoError(t, err)
+	assert.Equal(t, &Tags{Title: "Africa", Artist: "Toto", AlbumArtist: "Toto", Year: 1982, Genres: []string{"Rock", "Soft Rock"}}, tags)
+}
+
+func TestRead_ID3v22(t *testing.T) {
+	frame := func(id, text string) []byte {
+		body := id3Text(0, text)
+		return append([]byte{id[0], id[1], id[2], 0, 0, byte(len(body))}, body...)
+	}
+	file := id3Tag(2, frame("TT2", "Rosanna"), frame("TP1", "Toto"))
+
+	tags, err := Read(bytes.NewReader(file))
+
+	require.NoError(t, err)
+	assert.Equal(t, "Rosanna", tags.Title)
+	assert.Equal(t, "Toto", tags.Artist)
+}
+
+func TestRead_ID3v1(t *testing.T) {
+	file := append(make([]byte, 512), id3v1Tag("Hold the Line", "Toto", "Toto", "1978", 17)...)
+
+	tags, err := Read(bytes.NewReader(file))
+
+	require.NoError(t, err)
+	assert.Equal(t, &Tags{Title: "Hold the Line", Artist: "Toto", Album: "Toto", Year: 1978, Genres: []string{"Rock"}}, tags)
+}
+
+func TestRead_ID3v2FallsBackToV1(t *testing.T) {
+	file := id3Tag(3, id3Frame(3, "TIT2", id3Text(0, "Africa")))
+	file = append(file, make([]byte, 64)...)
+	file = append(file, id3v1Tag("Afr", "Toto", "Toto IV", "1982", 255)...)
+
+	tags, err := Read(bytes.NewReader(file))
+
+	require.NoError(t, err)
+	assert.Equal(t, &Tags{Title: "Africa", Artist: "Toto", Album: "Toto IV", Year: 1982}, tags)
+}
+
+func TestID3Genres(t *testing.T) {
+	assert.Equal(t, []string{"Rock"}, id3Genres("17"))
+	assert.Equal(t, []string{"Rock"}, id3Genres("(17)"))
+	assert.Equal(t, []string{"Rock", "Trance"}, id3Genres("(17)(31)"))
+	assert.Equal(t, []string{"Hard Rock"}, id3Genres("(17)Hard Rock"))
+	assert.Equal(t, []string{"Remix"}, id3Genres("(RX)"))
+	assert.Equal(t, []string{"Synthwave"}, id3Genres("Synthwave"))
+}
+
+func TestDecodeUTF16_MultipleValues(t *testing.T) {
+	body := []byte{0xff, 0xfe, 'A', 0, 0, 0, 0xfe, 0xff, 0, 'B'}
+
+	assert.Equal(t, "A\x00B", decodeUTF16(body, nil))
+}
+
+func TestResync(t *testing.T) {
+	assert.Equal(t, []byte{0xff, 0xe0, 0xff, 0x00}, resync([]byte{0xff, 0x00, 0xe0, 0xff, 0x00, 0x00}))
+}
@@ -0,0 +1,158 @@
+package tags
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// readMP4 reads the iTunes metadata list of an MP4/M4A file, found at
+// moov/udta/meta/ilst
+func readMP4(r io.ReadSeeker) (*Tags, error) {
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	path := []string{"moov", "udta", "meta", "ilst"}
+	for depth := 0; depth < len(path); {
+		atom, size, err := readAtomHeader(r, end)
+		if err != nil {
+			if err == io.EOF {
+				// No metadata
+				return &Tags{}, nil
+			}
+			return nil, err
+		}
+		start, _ := r.Seek(0, io.SeekCurrent)
+
+		if atom != path[depth] {
+			if _, err := r.Seek(size, io.SeekCurrent); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		end = start + size
+		depth++
+		if atom == "meta" {
+			// meta is a full atom with version and flags before its children
+			if _, err := r.Seek(4, io.SeekCurrent); err != nil {
+				return nil, err
+			}
+		}
+		if atom == "ilst" {
+			if size > maxTagSize {
+				return nil, fmt.Errorf("MP4 metadata of %d bytes is too large", size)
+			}
+			list := make([]byte, size)
+			if _, err := io.ReadFull(r, list); err != nil {
+				return nil, fmt.Errorf("truncated MP4 metadata: %w", err)
+			}
+			return parseMP4Items(list), nil
+		}
+	}
+
+	return &Tags{}, nil
+}
+
+// readAtomHeader reads the type and body size of the next atom before limit.
+// It returns io.EOF at the limit.
+func readAtomHeader(r io.ReadSeeker, limit int64) (string, int64, error) {
+	pos, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", 0, err
+	}
+	if pos+8 > limit {
+		return "", 0, io.EOF
+	}
+
+	var header [8]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return "", 0, fmt.Errorf("truncated MP4 atom: %w", err)
+	}
+	size := int64(binary.BigEndian.Uint32(header[:4]))
+	atom := string(header[4:8])
+	headerSize := int64(8)
+
+	switch size {
+	case 0:
+		// The atom runs to the end
+		size = limit - pos
+	case 1:
+		var large [8]byte
+		if _, err := io.ReadFull(r, large[:]); err != nil {
+			return "", 0, fmt.Errorf("truncated MP4 atom: %w", err)
+		}
+		size = int64(binary.BigEndian.Uint64(large[:]))
+		headerSize = 16
+	}
+	if size < headerSize || pos+size > limit {
+		return "", 0, fmt.Errorf("invalid MP4 atom '%s'", atom)
+	}
+	return atom, size - headerSize, nil
+}
+
+// parseMP4Items reads the metadata items in an ilst atom. Each item holds its
+// value in a data atom.
+func parseMP4Items(list []byte) *Tags {
+	tags := &Tags{}
+	for len(list) >= 8 {
+		size := int(binary.BigEndian.Uint32(list[:4]))
+		if size < 8 || size > len(list) {
+			break
+		}
+		item, body := string(list[4:8]), list[8:size]
+		list = list[size:]
+
+		value, ok := mp4Data(body)
+		if !ok {
+			continue
+		}
+		text := strings.TrimSpace(string(value))
+
+		switch item {
+		case "\xa9nam":
+			tags.Title = text
+		case "\xa9ART":
+			tags.Artist = text
+		case "aART":
+			tags.AlbumArtist = text
+		case "\xa9alb":
+			tags.Album = text
+		case "\xa9day":
+			tags.Year = parseYear(text)
+		case "\xa9gen":
+			tags.addGenre(text)
+		case "gnre":
+			// ID3v1 genre index plus one
+			if len(value) == 2 {
+				index := int(binary.BigEndian.Uint16(value)) - 1
+				if index >= 0 && index < len(id3v1Genres) {
+					tags.addGenre(id3v1Genres[index])
+				}
+			}
+		}
+	}
+	return tags
+}
+
+// mp4Data returns the value of the first data atom in an item
+func mp4Data(item []byte) ([]byte, bool) {
+	for len(item) >= 8 {
+		size := int(binary.BigEndian.Uint32(item[:4]))
+		if size < 8 || size > len(item) {
+			return nil, false
+		}
+		if string(item[4:8]) == "data" && size >= 16 {
+			// Type indicator and locale come before the value
+			return item[16:size], true
+		}
+		item = item[size:]
+	}
+	return nil, false
+}
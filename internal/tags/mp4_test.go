@@ -0,0 +1,65 @@
+package tags
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mp4Atom builds an atom with the children or body
+func mp4Atom(atom string, body ...[]byte) []byte {
+	content := bytes.Join(body, nil)
+	header := binary.BigEndian.AppendUint32(nil, uint32(8+len(content)))
+	return append(append(header, atom...), content...)
+}
+
+// mp4Item builds a metadata item holding a data atom
+func mp4Item(item string, value []byte) []byte {
+	data := append(make([]byte, 8), value...) // Type indicator and locale
+	return mp4Atom(item, mp4Atom("data", data))
+}
+
+func TestRead_MP4(t *testing.T) {
+	ilst := mp4Atom("ilst",
+		mp4Item("\xa9nam", []byte("Africa")),
+		mp4Item("\xa9ART", []byte("Toto")),
+		mp4Item("\xa9alb", []byte("Toto IV")),
+		mp4Item("\xa9day", []byte("1982-04-08T07:00:00Z")),
+		mp4Item("gnre", []byte{0, 18}),
+		mp4Item("covr", []byte{1, 2, 3}),
+	)
+	file := bytes.Join([][]byte{
+		mp4Atom("ftyp", []byte("M4A \x00\x00\x00\x00")),
+		mp4Atom("mdat", make([]byte, 100)),
+		mp4Atom("moov",
+			mp4Atom("mvhd", make([]byte, 20)),
+			mp4Atom("udta", mp4Atom("meta", make([]byte, 4), mp4Atom("hdlr", make([]byte, 25)), ilst)),
+		),
+	}, nil)
+
+	tags, err := Read(bytes.NewReader(file))
+
+	require.NoError(t, err)
+	assert.Equal(t, &Tags{Title: "Africa", Artist: "Toto", Album: "Toto IV", Year: 1982, Genres: []string{"Rock"}}, tags)
+}
+
+func TestRead_MP4WithoutMetadata(t *testing.T) {
+	file := append(mp4Atom("ftyp", []byte("M4A \x00\x00\x00\x00")), mp4Atom("moov", mp4Atom("mvhd", make([]byte, 20)))...)
+
+	tags, err := Read(bytes.NewReader(file))
+
+	require.NoError(t, err)
+	assert.Equal(t, &Tags{}, tags)
+}
+
+func TestRead_MP4BrokenAtom(t *testing.T) {
+	file := append(mp4Atom("ftyp", []byte("M4A \x00\x00\x00\x00")), 0, 0, 1, 0, 'm', 'o', 'o', 'v')
+
+	_, err := Read(bytes.NewReader(file))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid MP4 atom")
+}
@@ -0,0 +1,109 @@
+// Package tags reads song details from the metadata of music files: ID3 tags
+// in MP3s, Vorbis comments in FLAC, Ogg Vorbis and Opus files, and the iTunes
+// metadata atoms in MP4/M4A files.
+package tags
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupported is returned for files that aren't in a supported format
+var ErrUnsupported = errors.New("unsupported audio file")
+
+// maxTagSize caps how much of a file is read for its tags, cover art included
+const maxTagSize = 16 << 20
+
+// Tags are the song details stored in a music file. Fields the file doesn't
+// set are empty.
+type Tags struct {
+	Title       string
+	Artist      string
+	AlbumArtist string
+	Album       string
+	Genres      []string
+	Year        int
+}
+
+// ReadFile reads the tags of the music file at path
+func ReadFile(path string) (*Tags, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	tags, err := Read(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tags of %s: %w", path, err)
+	}
+	return tags, nil
+}
+
+// Read reads the tags of a music file, detecting its format from its content
+func Read(r io.ReadSeeker) (*Tags, error) {
+	var magic [8]byte
+	n, err := io.ReadFull(r, magic[:])
+	if err != nil && err != io.ErrUnexpectedEOF {
+		if err == io.EOF {
+			return nil, ErrUnsupported
+		}
+		return nil, err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(magic[:n], []byte("ID3")):
+		return readID3(r)
+	case bytes.HasPrefix(magic[:n], []byte("fLaC")):
+		return readFLAC(r)
+	case bytes.HasPrefix(magic[:n], []byte("OggS")):
+		return readOgg(r)
+	case n == 8 && string(magic[4:8]) == "ftyp":
+		return readMP4(r)
+	}
+
+	// MP3s without an ID3v2 tag may still have an ID3v1 tag at the end
+	tags, err := readID3v1(r)
+	if err != nil {
+		return nil, err
+	}
+	if tags == nil {
+		return nil, ErrUnsupported
+	}
+	return tags, nil
+}
+
+// parseYear reads the year from dates like "1986", "1986-04-08" or "1986/04"
+func parseYear(date string) int {
+	date = strings.TrimSpace(date)
+	if len(date) < 4 {
+		return 0
+	}
+	year, err := strconv.Atoi(date[:4])
+	if err != nil || year <= 0 {
+		return 0
+	}
+	return year
+}
+
+// addGenre appends a genre that isn't empty or already listed
+func (t *Tags) addGenre(genre string) {
+	genre = strings.TrimSpace(genre)
+	if genre == "" {
+		return
+	}
+	for _, existing := range t.Genres {
+		if strings.EqualFold(existing, genre) {
+			return
+		}
+	}
+	t.Genres = append(t.Genres, genre)
+}
@@ -0,0 +1,49 @@
+package tags
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRead_Unsupported(t *testing.T) {
+	_, err := Read(bytes.NewReader(nil))
+	assert.ErrorIs(t, err, ErrUnsupported)
+
+	_, err = Read(bytes.NewReader([]byte("RIFF....WAVEfmt ")))
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestReadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "song.mp3")
+	require.NoError(t, os.WriteFile(path, append(make([]byte, 64), id3v1Tag("Africa", "Toto", "", "", 255)...), 0644))
+
+	tags, err := ReadFile(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Africa", tags.Title)
+
+	_, err = ReadFile(filepath.Join(t.TempDir(), "missing.mp3"))
+	require.Error(t, err)
+}
+
+func TestReadFile_WrapsErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	require.NoError(t, os.WriteFile(path, []byte("not music"), 0644))
+
+	_, err := ReadFile(path)
+
+	assert.ErrorIs(t, err, ErrUnsupported)
+	assert.Contains(t, err.Error(), "notes.txt")
+}
+
+func TestParseYear(t *testing.T) {
+	assert.Equal(t, 1986, parseYear("1986"))
+	assert.Equal(t, 1986, parseYear(" 1986-04-08 "))
+	assert.Equal(t, 0, parseYear("86"))
+	assert.Equal(t, 0, parseYear("unknown"))
+}
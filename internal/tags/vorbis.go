@@ -0,0 +1,174 @@
+package tags
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// flacVorbisComment is the FLAC metadata block type holding the tags
+const flacVorbisComment = 4
+
+// readFLAC reads the Vorbis comment block of a FLAC file
+func readFLAC(r io.Reader) (*Tags, error) {
+	var magic [4]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return nil, fmt.Errorf("invalid FLAC header: %w", err)
+	}
+
+	for {
+		var header [4]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return nil, fmt.Errorf("truncated FLAC metadata: %w", err)
+		}
+		last := header[0]&0x80 != 0
+		blockType := header[0] & 0x7f
+		size := int64(header[1])<<16 | int64(header[2])<<8 | int64(header[3])
+
+		if blockType == flacVorbisComment {
+			block := make([]byte, size)
+			if _, err := io.ReadFull(r, block); err != nil {
+				return nil, fmt.Errorf("truncated FLAC metadata: %w", err)
+			}
+			return parseVorbisComment(block)
+		}
+
+		if _, err := io.CopyN(io.Discard, r, size); err != nil {
+			return nil, fmt.Errorf("truncated FLAC metadata: %w", err)
+		}
+		if last {
+			return &Tags{}, nil
+		}
+	}
+}
+
+// Headers of the comment packet in Ogg streams
+var (
+	vorbisCommentHeader = []byte("\x03vorbis")
+	opusCommentHeader   = []byte("OpusTags")
+)
+
+// readOgg reads the comment header, the second packet of an Ogg Vorbis or
+// Opus stream
+func readOgg(r io.Reader) (*Tags, error) {
+	var (
+		packet  []byte
+		packets int
+	)
+
+	for packets < 2 {
+		var header [27]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return nil, fmt.Errorf("truncated Ogg stream: %w", err)
+		}
+		if string(header[:4]) != "OggS" {
+			return nil, fmt.Errorf("invalid Ogg page")
+		}
+
+		segments := make([]byte, header[26])
+		if _, err := io.ReadFull(r, segments); err != nil {
+			return nil, fmt.Errorf("truncated Ogg stream: %w", err)
+		}
+
+		for _, size := range segments {
+			data := make([]byte, size)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return nil, fmt.Errorf("truncated Ogg stream: %w", err)
+			}
+			if packets == 1 {
+				packet = append(packet, data...)
+				if len(packet) > maxTagSize {
+					return nil, fmt.Errorf("Ogg comment header is too large")
+				}
+			}
+			// A segment shorter than 255 bytes ends the packet
+			if size < 255 {
+				packets++
+				if packets == 2 {
+					break
+				}
+			}
+		}
+	}
+
+	switch {
+	case bytes.HasPrefix(packet, vorbisCommentHeader):
+		return parseVorbisComment(packet[len(vorbisCommentHeader):])
+	case bytes.HasPrefix(packet, opusCommentHeader):
+		return parseVorbisComment(packet[len(opusCommentHeader):])
+	}
+	return nil, ErrUnsupported
+}
+
+// parseVorbisComment reads the KEY=value fields of a Vorbis comment
+func parseVorbisComment(data []byte) (*Tags, error) {
+	next := func() ([]byte, bool) {
+		if len(data) < 4 {
+			return nil, false
+		}
+		size := binary.LittleEndian.Uint32(data[:4])
+		if uint64(size) > uint64(len(data)-4) {
+			return nil, false
+		}
+		field := data[4 : 4+size]
+		data = data[4+size:]
+		return field, true
+	}
+
+	// Skip the vendor string
+	if _, ok := next(); !ok {
+		return nil, fmt.Errorf("invalid Vorbis comment")
+	}
+	if len(data) < 4 {
+		return nil, fmt.Errorf("invalid Vorbis comment")
+	}
+	count := binary.LittleEndian.Uint32(data[:4])
+	data = data[4:]
+
+	tags := &Tags{}
+	var date, year string
+	for i := uint32(0); i < count; i++ {
+		field, ok := next()
+		if !ok {
+			return nil, fmt.Errorf("invalid Vorbis comment")
+		}
+		key, value, found := strings.Cut(string(field), "=")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.ToUpper(key) {
+		case "TITLE":
+			if tags.Title == "" {
+				tags.Title = value
+			}
+		case "ARTIST":
+			if tags.Artist == "" {
+				tags.Artist = value
+			}
+		case "ALBUMARTIST", "ALBUM ARTIST":
+			if tags.AlbumArtist == "" {
+				tags.AlbumArtist = value
+			}
+		case "ALBUM":
+			if tags.Album == "" {
+				tags.Album = value
+			}
+		case "DATE":
+			date = value
+		case "YEAR":
+			year = value
+		case "GENRE":
+			tags.addGenre(value)
+		}
+	}
+
+	tags.Year = parseYear(date)
+	if tags.Year == 0 {
+		tags.Year = parseYear(year)
+	}
+	return tags, nil
+}
@@ -0,0 +1,112 @@
+package tags
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// vorbisComment builds a Vorbis comment with the fields
+func vorbisComment(fields ...string) []byte {
+	var buf bytes.Buffer
+	writeString := func(s string) {
+		binary.Write(&buf, binary.LittleEndian, uint32(len(s)))
+		buf.WriteString(s)
+	}
+	writeString("test vendor")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(fields)))
+	for _, field := range fields {
+		writeString(field)
+	}
+	return buf.Bytes()
+}
+
+// flacBlock builds a FLAC metadata block header and body
+func flacBlock(blockType byte, last bool, body []byte) []byte {
+	if last {
+		blockType |= 0x80
+	}
+	header := []byte{blockType, byte(len(body) >> 16), byte(len(body) >> 8), byte(len(body))}
+	return append(header, body...)
+}
+
+// oggPage builds an Ogg page holding whole packets
+func oggPage(packets ...[]byte) []byte {
+	var segments, body []byte
+	for _, packet := range packets {
+		n := len(packet)
+		for n >= 255 {
+			segments = append(segments, 255)
+			n -= 255
+		}
+		segments = append(segments, byte(n))
+		body = append(body, packet...)
+	}
+
+	header := make([]byte, 27)
+	copy(header, "OggS")
+	header[26] = byte(len(segments))
+	page := append(header, segments...)
+	return append(page, body...)
+}
+
+func TestRead_FLAC(t *testing.T) {
+	var file []byte
+	file = append(file, "fLaC"...)
+	file = append(file, flacBlock(0, false, make([]byte, 34))...)
+	file = append(file, flacBlock(flacVorbisComment, true, vorbisComment(
+		"TITLE=One More Time",
+		"artist=Daft Punk",
+		"ALBUMARTIST=Daft Punk",
+		"ALBUM=Discovery",
+		"DATE=2000-11-30",
+		"GENRE=House",
+		"GENRE=French House",
+		"GENRE=house",
+		"broken",
+	))...)
+
+	tags, err := Read(bytes.NewReader(file))
+
+	require.NoError(t, err)
+	assert.Equal(t, &Tags{Title: "One More Time", Artist: "Daft Punk", AlbumArtist: "Daft Punk", Album: "Discovery", Year: 2000, Genres: []string{"House", "French House"}}, tags)
+}
+
+func TestRead_FLACWithoutComments(t *testing.T) {
+	file := append([]byte("fLaC"), flacBlock(0, true, make([]byte, 34))...)
+
+	tags, err := Read(bytes.NewReader(file))
+
+	require.NoError(t, err)
+	assert.Equal(t, &Tags{}, tags)
+}
+
+func TestRead_OggVorbis(t *testing.T) {
+	// The comment packet spans several segments
+	comment := append([]byte("\x03vorbis"), vorbisComment("TITLE=Hold the Line", "ARTIST=Toto", "YEAR=1978", "COMMENT="+string(make([]byte, 300)))...)
+	file := append(oggPage([]byte("\x01vorbis identification")), oggPage(comment)...)
+
+	tags, err := Read(bytes.NewReader(file))
+
+	require.NoError(t, err)
+	assert.Equal(t, &Tags{Title: "Hold the Line", Artist: "Toto", Year: 1978}, tags)
+}
+
+func TestRead_Opus(t *testing.T) {
+	file := oggPage([]byte("OpusHead"), append([]byte("OpusTags"), vorbisComment("TITLE=Rosanna", "ARTIST=Toto")...))
+
+	tags, err := Read(bytes.NewReader(file))
+
+	require.NoError(t, err)
+	assert.Equal(t, "Rosanna", tags.Title)
+	assert.Equal(t, "Toto", tags.Artist)
+}
+
+func TestParseVorbisComment_Invalid(t *testing.T) {
+	_, err := parseVorbisComment([]byte{0xff, 0, 0, 0})
+
+	require.Error(t, err)
+}
@@ -0,0 +1,29 @@
+// Package visibility defines who can see and edit a playlist, shared by the
+// configuration, the Spotify client and sync manifests.
+package visibility
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Visibility controls who can see and edit a playlist
+type Visibility string
+
+const (
+	Private       Visibility = "private"
+	Public        Visibility = "public"
+	Collaborative Visibility = "collaborative" // Private, but editable by invited users
+)
+
+// Parse parses "private", "public" or "collaborative". An empty string means
+// no visibility was chosen.
+func Parse(value string) (Visibility, error) {
+	visibility := Visibility(strings.ToLower(strings.TrimSpace(value)))
+	switch visibility {
+	case "", Private, Public, Collaborative:
+		return visibility, nil
+	default:
+		return "", fmt.Errorf("invalid playlist visibility %q: must be private, public or collaborative", value)
+	}
+}
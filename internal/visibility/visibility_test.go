@@ -0,0 +1,34 @@
+package visibility
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Visibility
+		wantErr  bool
+	}{
+		{input: "", expected: ""},
+		{input: "private", expected: Private},
+		{input: " Public ", expected: Public},
+		{input: "COLLABORATIVE", expected: Collaborative},
+		{input: "secret", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			visibility, err := Parse(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, visibility)
+		})
+	}
+}
@@ -6,8 +6,12 @@ import (
 	"os"
 
 	"auto-spotify/cmd"
+	"auto-spotify/internal/cache"
 	"auto-spotify/internal/config"
+	"auto-spotify/internal/history"
 	"auto-spotify/internal/openai"
+	"auto-spotify/internal/overrides"
+	"auto-spotify/internal/registry"
 	"auto-spotify/internal/spotify"
 )
 
@@ -24,17 +28,93 @@ func main() {
 		openaiService = openai.NewService(cfg.OpenAI.APIKey)
 	}
 	spotifyService := spotify.NewService(cfg.Spotify.ClientID, cfg.Spotify.ClientSecret, cfg.Spotify.RedirectURL)
+	spotifyService.SetMarket(cfg.Spotify.Market)
+	spotifyService.SetVisibility(cfg.Spotify.Visibility)
+
+	// Open the search result cache (optional, a zero TTL disables it)
+	var searchCache *cache.Cache
+	if cfg.Cache.TTL > 0 {
+		searchCache, err = openState(cfg.Cache.Path, cache.DefaultPath, func(path string) (*cache.Cache, error) {
+			return cache.Open(path, cfg.Cache.TTL)
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: search cache disabled: %v\n", err)
+		}
+		spotifyService.SetCache(searchCache)
+	}
+
+	// Load manual match overrides (optional)
+	matchOverrides, err := openState(cfg.Overrides.Path, overrides.DefaultPath, overrides.Load)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: match overrides disabled: %v\n", err)
+	}
+	spotifyService.SetOverrides(matchOverrides)
+
+	// Open the registry of generated playlists (optional)
+	playlistRegistry, err := openState(cfg.Registry.Path, registry.DefaultPath, registry.Open)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: playlist registry disabled: %v\n", err)
+	}
+	spotifyService.SetRegistry(playlistRegistry)
+
+	// Open the history of playlist versions (optional)
+	playlistHistory, err := openState(cfg.History.Path, history.DefaultPath, history.Open)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: playlist history disabled: %v\n", err)
+	}
+	spotifyService.SetHistory(playlistHistory)
 
 	// Setup root command
 	rootCmd := cmd.NewRootCmd(openaiService, spotifyService)
 
 	// Add export subcommand
-	exportCmd := cmd.NewExportCmd(spotifyService)
+	exportCmd := cmd.NewExportCmd(spotifyService, cfg.Library.Root)
 	rootCmd.AddCommand(exportCmd)
 
+	// Add import-library subcommand
+	importLibraryCmd := cmd.NewImportLibraryCmd(spotifyService, cfg.Library.Root)
+	rootCmd.AddCommand(importLibraryCmd)
+
+	// Add sync subcommand
+	syncCmd := cmd.NewSyncCmd(openaiService, spotifyService)
+	rootCmd.AddCommand(syncCmd)
+
+	// Add dedupe subcommand
+	dedupeCmd := cmd.NewDedupeCmd(spotifyService)
+	rootCmd.AddCommand(dedupeCmd)
+
+	// Add cache subcommand
+	cacheCmd := cmd.NewCacheCmd(searchCache)
+	rootCmd.AddCommand(cacheCmd)
+
+	// Add registry subcommand
+	registryCmd := cmd.NewRegistryCmd(playlistRegistry)
+	rootCmd.AddCommand(registryCmd)
+
+	// Add history and undo subcommands
+	historyCmd := cmd.NewHistoryCmd(spotifyService)
+	rootCmd.AddCommand(historyCmd)
+	undoCmd := cmd.NewUndoCmd(spotifyService)
+	rootCmd.AddCommand(undoCmd)
+
 	// Execute
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// openState opens a state file at the configured location, or at the
+// default one when none is configured
+func openState[T any](configured string, defaultPath func() (string, error), open func(path string) (T, error)) (T, error) {
+	path := configured
+	if path == "" {
+		var err error
+		path, err = defaultPath()
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+	return open(path)
+}
@@ -1,10 +1,15 @@
 package main
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
 	"testing"
 
+	"auto-spotify/internal/history"
+
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMain(t *testing.T) {
@@ -39,6 +44,23 @@ func TestMainWithMissingEnv(t *testing.T) {
 	assert.NotNil(t, main)
 }
 
+func TestOpenState(t *testing.T) {
+	configured := filepath.Join(t.TempDir(), "history.json")
+	defaultPath := func() (string, error) { return "", errors.New("no config directory") }
+
+	h, err := openState(configured, defaultPath, history.Open)
+	require.NoError(t, err)
+	assert.Equal(t, configured, h.Path())
+
+	fallback := filepath.Join(t.TempDir(), "default.json")
+	h, err = openState("", func() (string, error) { return fallback, nil }, history.Open)
+	require.NoError(t, err)
+	assert.Equal(t, fallback, h.Path())
+
+	_, err = openState("", defaultPath, history.Open)
+	assert.Error(t, err)
+}
+
 // Helper function to set or unset environment variable
 func setOrUnsetEnv(key, value string) {
 	if value == "" {